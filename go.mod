@@ -4,5 +4,6 @@ go 1.18
 
 require (
 	golang.org/x/exp v0.0.0-20221208152030-732eee02a75a
+	golang.org/x/sync v0.1.0
 	golang.org/x/text v0.9.0
 )
@@ -0,0 +1,44 @@
+// Copyright 2023 dudaodong@gmail.com. All rights reserved.
+// Use of this source code is governed by MIT license
+
+package stream
+
+import "container/heap"
+
+// Heap is a binary min heap according to the less function, it can be used as a priority queue.
+// Heap should be created by Stream.ToHeap, the zero value is an empty heap without less function, which can't be pushed.
+type Heap[T any] struct {
+	h *lessHeap[T]
+}
+
+// Push pushes the value into the heap.
+// Play: todo
+func (h *Heap[T]) Push(value T) {
+	if h.h == nil {
+		panic("stream.Heap: heap should be created by ToHeap")
+	}
+
+	heap.Push(h.h, value)
+}
+
+// Pop returns the minimum value and removes it from the heap, or zero value and false if the heap is empty.
+// Play: todo
+func (h *Heap[T]) Pop() (T, bool) {
+	var result T
+
+	if h.Len() == 0 {
+		return result, false
+	}
+
+	return heap.Pop(h.h).(T), true
+}
+
+// Len returns the number of values in the heap.
+// Play: todo
+func (h *Heap[T]) Len() int {
+	if h.h == nil {
+		return 0
+	}
+
+	return h.h.Len()
+}
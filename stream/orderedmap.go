@@ -0,0 +1,74 @@
+// Copyright 2023 dudaodong@gmail.com. All rights reserved.
+// Use of this source code is governed by MIT license
+
+package stream
+
+// OrderedMap is a map which keeps the insertion order of keys for iteration. the zero value is an empty map ready to use.
+type OrderedMap[K comparable, V any] struct {
+	keys []K
+	data map[K]V
+}
+
+// NewOrderedMap creates an empty OrderedMap.
+// Play: todo
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{
+		keys: make([]K, 0),
+		data: make(map[K]V),
+	}
+}
+
+// Set sets the value of key, a new key is appended to the end, an existing key keeps its original position.
+// Play: todo
+func (om *OrderedMap[K, V]) Set(key K, value V) {
+	if om.data == nil {
+		om.data = make(map[K]V)
+	}
+
+	if _, ok := om.data[key]; !ok {
+		om.keys = append(om.keys, key)
+	}
+	om.data[key] = value
+}
+
+// Get returns the value of key and whether the key exists.
+// Play: todo
+func (om *OrderedMap[K, V]) Get(key K) (V, bool) {
+	value, ok := om.data[key]
+	return value, ok
+}
+
+// Len returns the number of keys in the map.
+// Play: todo
+func (om *OrderedMap[K, V]) Len() int {
+	return len(om.keys)
+}
+
+// Keys returns the keys of the map in insertion order.
+// Play: todo
+func (om *OrderedMap[K, V]) Keys() []K {
+	keys := make([]K, len(om.keys))
+	copy(keys, om.keys)
+
+	return keys
+}
+
+// Values returns the values of the map in insertion order of their keys.
+// Play: todo
+func (om *OrderedMap[K, V]) Values() []V {
+	values := make([]V, len(om.keys))
+
+	for i, k := range om.keys {
+		values[i] = om.data[k]
+	}
+
+	return values
+}
+
+// ForEach calls the iteratee function for each key/value pair of the map in insertion order.
+// Play: todo
+func (om *OrderedMap[K, V]) ForEach(iteratee func(key K, value V)) {
+	for _, k := range om.keys {
+		iteratee(k, om.data[k])
+	}
+}
@@ -7,10 +7,24 @@ package stream
 
 import (
 	"bytes"
+	"container/heap"
+	"context"
+	"encoding/csv"
 	"encoding/gob"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/duke-git/lancet/v2/slice"
 	"golang.org/x/exp/constraints"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
 )
 
 // A stream should implements methods:
@@ -51,12 +65,56 @@ type Stream[T any] struct {
 	source []T
 }
 
+// Pair is a key/value pair, used as the element of stream created from map.
+type Pair[K any, V any] struct {
+	Key K
+	Val V
+}
+
 // Of creates a stream whose elements are the specified values.
 // Play: https://go.dev/play/p/jI6_iZZuVFE
 func Of[T any](elems ...T) Stream[T] {
 	return FromSlice(elems)
 }
 
+// OfLimited creates a stream whose elements are at most max of the specified values, the rest values are dropped.
+// if max is not positive, an empty stream will be returned.
+// Play: todo
+func OfLimited[T any](max int, elems ...T) Stream[T] {
+	if max < 0 {
+		max = 0
+	}
+	if max > len(elems) {
+		max = len(elems)
+	}
+
+	source := make([]T, max)
+	copy(source, elems[:max])
+
+	return FromSlice(source)
+}
+
+// FromNonZero creates a stream whose elements are the specified values which are not zero value.
+// Play: todo
+func FromNonZero[T comparable](elems ...T) Stream[T] {
+	var zeroValue T
+	source := make([]T, 0, len(elems))
+
+	for _, v := range elems {
+		if v != zeroValue {
+			source = append(source, v)
+		}
+	}
+
+	return FromSlice(source)
+}
+
+// OfSlices creates a stream whose elements are the elements of the specified slices in order.
+// Play: todo
+func OfSlices[T any](slices ...[]T) Stream[T] {
+	return Flatten(FromSlice(slices))
+}
+
 // Generate stream where each element is generated by the provided generater function
 // Play: https://go.dev/play/p/rkOWL1yA3j9
 func Generate[T any](generator func() func() (item T, ok bool)) Stream[T] {
@@ -80,6 +138,24 @@ func FromSlice[T any](source []T) Stream[T] {
 	return Stream[T]{source: source}
 }
 
+// FromSliceOwned creates stream from slice, the stream takes the ownership of source without copying it.
+// it behaves the same as FromSlice, which shares source too, the name only makes the ownership explicit at the call site.
+// source should not be used by caller anymore, since it is shared with the stream, eg. the slice returned by ToSlice.
+// no operation of stream modifies the elements in place yet, so currently the sharing is only visible through ToSlice.
+// Play: todo
+func FromSliceOwned[T any](source []T) Stream[T] {
+	return FromSlice(source)
+}
+
+// FromSliceCopy creates stream from a copy of slice, so the later changes of source don't affect the stream and vice versa.
+// Play: todo
+func FromSliceCopy[T any](source []T) Stream[T] {
+	s := make([]T, len(source))
+	copy(s, source)
+
+	return FromSlice(s)
+}
+
 // FromChannel creates stream from channel.
 // Play: https://go.dev/play/p/9TZYugGMhXZ
 func FromChannel[T any](source <-chan T) Stream[T] {
@@ -92,7 +168,59 @@ func FromChannel[T any](source <-chan T) Stream[T] {
 	return FromSlice(s)
 }
 
+// FromMap creates a stream of key/value pairs from map.
+// the order of the pairs is unspecified since the iteration order of map is random, use FromMapSorted for a deterministic order.
+// Play: todo
+func FromMap[K comparable, V any](m map[K]V) Stream[Pair[K, V]] {
+	source := make([]Pair[K, V], 0, len(m))
+
+	for k, v := range m {
+		source = append(source, Pair[K, V]{Key: k, Val: v})
+	}
+
+	return FromSlice(source)
+}
+
+// FromMapSorted creates a stream of key/value pairs from map, the pairs are sorted by key according to the provided less function.
+// Play: todo
+func FromMapSorted[K comparable, V any](m map[K]V, less func(a, b K) bool) Stream[Pair[K, V]] {
+	source := FromMap(m).source
+
+	slice.SortBy(source, func(a, b Pair[K, V]) bool {
+		return less(a.Key, b.Key)
+	})
+
+	return FromSlice(source)
+}
+
+// FromCSV creates stream from csv data read from r, each record is converted to element by the mapper.
+// it returns the first error of reading csv or mapping record.
+// Play: todo
+func FromCSV[T any](r io.Reader, mapper func(record []string) (T, error)) (Stream[T], error) {
+	reader := csv.NewReader(r)
+	source := make([]T, 0)
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Empty[T](), err
+		}
+
+		item, err := mapper(record)
+		if err != nil {
+			return Empty[T](), err
+		}
+		source = append(source, item)
+	}
+
+	return FromSlice(source), nil
+}
+
 // FromRange creates a number stream from start to end. both start and end are included. [start, end]
+// it panics if the range has too many elements to be held in a slice.
 // Play: https://go.dev/play/p/9Ex1-zcg-B-
 func FromRange[T constraints.Integer | constraints.Float](start, end, step T) Stream[T] {
 	if end < start {
@@ -101,13 +229,123 @@ func FromRange[T constraints.Integer | constraints.Float](start, end, step T) St
 		panic("stream.FromRange: param step should be positive")
 	}
 
-	l := int((end-start)/step) + 1
+	if isInteger[T]() {
+		// the difference is computed in uint64, so it can't overflow for any integer type.
+		n := (uint64(end) - uint64(start)) / uint64(step)
+		if n >= uint64(math.MaxInt) {
+			panic("stream.FromRange: range is too large")
+		}
+
+		l := int(n) + 1
+		source := make([]T, l)
+
+		source[0] = start
+		for i := 1; i < l; i++ {
+			source[i] = source[i-1] + step
+		}
+
+		return FromSlice(source)
+	}
+
+	n := float64((end - start) / step)
+	if !(n < math.MaxInt) {
+		panic("stream.FromRange: range is too large")
+	}
+
+	// n may be slightly below or above an integer because of floating-point rounding (eg. 0.3/0.1 is 2.9999999999999996),
+	// it is rounded if the difference is within the rounding error, so that end is not lost.
+	magnitude := math.Max(math.Abs(float64(start)), math.Abs(float64(end))) / float64(step)
+	tolerance := 4 * float64(floatEpsilon[T]()) * (n + magnitude)
+
+	reachEnd := false
+	if r := math.Round(n); math.Abs(n-r) <= tolerance {
+		n = r
+		reachEnd = true
+	}
+
+	l := int(n) + 1
 	source := make([]T, l)
 
 	for i := 0; i < l; i++ {
 		source[i] = start + (T(i) * step)
 	}
 
+	// the last element may not be end exactly, or exceed end because of floating-point rounding.
+	if reachEnd || source[l-1] > end {
+		source[l-1] = end
+	}
+
+	return FromSlice(source)
+}
+
+// floatEpsilon returns the difference between 1 and the next representable value of float type T.
+func floatEpsilon[T constraints.Integer | constraints.Float]() T {
+	var one, eps T = 1, 1
+	for one+eps/2 != one {
+		eps /= 2
+	}
+
+	return eps
+}
+
+// FromRangeN creates a number stream which has exactly n elements, starting from start and increasing by step. [start, start+step, ... start+(n-1)*step]
+// Play: todo
+func FromRangeN[T constraints.Integer | constraints.Float](start, step T, n int) Stream[T] {
+	if n < 0 {
+		panic("stream.FromRangeN: param n should not be negative")
+	}
+
+	source := make([]T, n)
+
+	for i := 0; i < n; i++ {
+		source[i] = start + (T(i) * step)
+	}
+
+	return FromSlice(source)
+}
+
+func isInteger[T constraints.Integer | constraints.Float]() bool {
+	var half T = 1
+	half /= 2
+
+	return half == 0
+}
+
+// Empty creates a stream which has no elements.
+// Play: todo
+func Empty[T any]() Stream[T] {
+	return FromSlice([]T{})
+}
+
+// Repeat creates a stream which contains count copies of the value.
+// Play: todo
+func Repeat[T any](value T, count int) Stream[T] {
+	if count < 0 {
+		panic("stream.Repeat: param count should not be negative")
+	}
+
+	source := make([]T, count)
+
+	for i := 0; i < count; i++ {
+		source[i] = value
+	}
+
+	return FromSlice(source)
+}
+
+// Cycle creates a stream which repeats the elements of given slice in order for times times.
+// Play: todo
+func Cycle[T any](elems []T, times int) Stream[T] {
+	if times < 0 {
+		panic("stream.Cycle: param times should not be negative")
+	}
+
+	source := make([]T, 0, len(elems)*times)
+
+	for i := 0; i < times; i++ {
+		source = append(source, elems...)
+	}
+
 	return FromSlice(source)
 }
 
@@ -122,6 +360,24 @@ func Concat[T any](a, b Stream[T]) Stream[T] {
 	return FromSlice(source)
 }
 
+// ConcatDistinct creates a stream whose elements are all the distinct elements of the given streams, in first seen order.
+// Play: todo
+func ConcatDistinct[T comparable](streams ...Stream[T]) Stream[T] {
+	source := make([]T, 0)
+	seen := make(map[T]struct{})
+
+	for _, s := range streams {
+		for _, v := range s.source {
+			if _, ok := seen[v]; !ok {
+				seen[v] = struct{}{}
+				source = append(source, v)
+			}
+		}
+	}
+
+	return FromSlice(source)
+}
+
 // Distinct returns a stream that removes the duplicated items.
 // Play: https://go.dev/play/p/eGkOSrm64cB
 func (s Stream[T]) Distinct() Stream[T] {
@@ -141,6 +397,96 @@ func (s Stream[T]) Distinct() Stream[T] {
 	return FromSlice(source)
 }
 
+// DistinctSortedAssumeSorted returns a stream that removes the duplicated items of an already sorted stream.
+// it only compares adjacent elements in a single pass, so the stream must be sorted, otherwise non-adjacent duplicates will be kept.
+// Play: todo
+func DistinctSortedAssumeSorted[T comparable](s Stream[T]) Stream[T] {
+	return DedupConsecutive(s)
+}
+
+// DistinctByWithKeys returns a stream that removes the elements with duplicated key computed by keyer, the first element of each key is kept,
+// and the distinct keys in first seen order.
+// Play: todo
+func DistinctByWithKeys[T any, K comparable](s Stream[T], keyer func(item T) K) (Stream[T], []K) {
+	source := make([]T, 0)
+	keys := make([]K, 0)
+	seen := make(map[K]struct{})
+
+	for _, v := range s.source {
+		k := keyer(v)
+		if _, ok := seen[k]; !ok {
+			seen[k] = struct{}{}
+			source = append(source, v)
+			keys = append(keys, k)
+		}
+	}
+
+	return FromSlice(source), keys
+}
+
+// DistinctByKeepLatest returns a stream that removes the elements with duplicated key computed by keyer, the last element of each key is kept.
+// the kept elements are in the order of their last occurrence in stream, so later elements supersede the earlier ones with the same key,
+// which is different from keeping the first seen element of each key.
+// Play: todo
+func DistinctByKeepLatest[T any, K comparable](s Stream[T], keyer func(item T) K) Stream[T] {
+	l := len(s.source)
+	keys := make([]K, l)
+	last := make(map[K]int, l)
+
+	for i, v := range s.source {
+		keys[i] = keyer(v)
+		last[keys[i]] = i
+	}
+
+	source := make([]T, 0, len(last))
+	for i, v := range s.source {
+		if last[keys[i]] == i {
+			source = append(source, v)
+		}
+	}
+
+	return FromSlice(source)
+}
+
+// DedupConsecutive returns a stream that collapses the runs of consecutive equal elements into one, like unix uniq.
+// unlike Distinct, the same element is kept again if it is not adjacent to the previous one.
+// Play: todo
+func DedupConsecutive[T comparable](s Stream[T]) Stream[T] {
+	source := make([]T, 0)
+
+	for i, v := range s.source {
+		if i == 0 || v != s.source[i-1] {
+			source = append(source, v)
+		}
+	}
+
+	return FromSlice(source)
+}
+
+// DistinctDeep returns a stream that removes the duplicated items, the items are compared with reflect.DeepEqual.
+// it works for the types which can't be encoded by gob (eg. struct with only unexported fields, or interface field holding an unregistered type),
+// but the time complexity is O(n²), use Distinct or DistinctSortedAssumeSorted for large data.
+// note that reflect.DeepEqual never treats two non-nil func values as equal, so the elements with non-nil func field are never deduplicated.
+// Play: todo
+func (s Stream[T]) DistinctDeep() Stream[T] {
+	source := make([]T, 0)
+
+	for _, v := range s.source {
+		duplicated := false
+		for _, kept := range source {
+			if reflect.DeepEqual(v, kept) {
+				duplicated = true
+				break
+			}
+		}
+		if !duplicated {
+			source = append(source, v)
+		}
+	}
+
+	return FromSlice(source)
+}
+
 func hashKey(data any) string {
 	buffer := bytes.NewBuffer(nil)
 	encoder := gob.NewEncoder(buffer)
@@ -165,6 +511,41 @@ func (s Stream[T]) Filter(predicate func(item T) bool) Stream[T] {
 	return FromSlice(source)
 }
 
+// FilterDistinct returns a stream consisting of the distinct elements of this stream that match the given predicate, in first seen order.
+// Play: todo
+func FilterDistinct[T comparable](s Stream[T], predicate func(item T) bool) Stream[T] {
+	source := make([]T, 0)
+	seen := make(map[T]struct{})
+
+	for _, v := range s.source {
+		if _, ok := seen[v]; ok || !predicate(v) {
+			continue
+		}
+		seen[v] = struct{}{}
+		source = append(source, v)
+	}
+
+	return FromSlice(source)
+}
+
+// FilterContext returns a stream consisting of the elements of this stream that match the given predicate.
+// the context is checked before testing each element, if it is done, the filtering stops and returns the partial result with ctx.Err().
+// Play: todo
+func (s Stream[T]) FilterContext(ctx context.Context, predicate func(item T) bool) (Stream[T], error) {
+	source := make([]T, 0)
+
+	for _, v := range s.source {
+		if err := ctx.Err(); err != nil {
+			return FromSlice(source), err
+		}
+		if predicate(v) {
+			source = append(source, v)
+		}
+	}
+
+	return FromSlice(source), nil
+}
+
 // Map returns a stream consisting of the elements of this stream that apply the given function to elements of stream.
 // Play: https://go.dev/play/p/OtNQUImdYko
 func (s Stream[T]) Map(mapper func(item T) T) Stream[T] {
@@ -177,104 +558,695 @@ func (s Stream[T]) Map(mapper func(item T) T) Stream[T] {
 	return FromSlice(source)
 }
 
-// Peek returns a stream consisting of the elements of this stream, additionally performing the provided action on each element as elements are consumed from the resulting stream.
-// Play: https://go.dev/play/p/u1VNzHs6cb2
-func (s Stream[T]) Peek(consumer func(item T)) Stream[T] {
+// FilterMap returns a stream consisting of the results of applying the given function to the elements of stream.
+// the function returns the transformed value and a flag, only the values whose flag is true are kept.
+// Play: todo
+func FilterMap[T, R any](s Stream[T], fn func(item T) (R, bool)) Stream[R] {
+	source := make([]R, 0)
+
+	for _, v := range s.source {
+		if r, ok := fn(v); ok {
+			source = append(source, r)
+		}
+	}
+
+	return FromSlice(source)
+}
+
+// MapUntilError returns a stream consisting of the results of applying the given mapper to the elements of stream in order.
+// it stops at the first error, and returns the stream of successfully mapped elements before it along with the error.
+// Play: todo
+func MapUntilError[T, R any](s Stream[T], mapper func(item T) (R, error)) (Stream[R], error) {
+	source := make([]R, 0, len(s.source))
+
+	for _, v := range s.source {
+		r, err := mapper(v)
+		if err != nil {
+			return FromSlice(source), err
+		}
+		source = append(source, r)
+	}
+
+	return FromSlice(source), nil
+}
+
+// MapErr returns a stream consisting of the results of applying the given mapper to the elements of stream.
+// it stops at the first error, and returns an empty stream with the error wrapped with the index of the element.
+// Play: todo
+func MapErr[T, R any](s Stream[T], mapper func(item T) (R, error)) (Stream[R], error) {
+	source := make([]R, len(s.source))
+
+	for i, v := range s.source {
+		r, err := mapper(v)
+		if err != nil {
+			return Empty[R](), fmt.Errorf("stream.MapErr: element at index %d: %w", i, err)
+		}
+		source[i] = r
+	}
+
+	return FromSlice(source), nil
+}
+
+// MapMemoized returns a stream consisting of the results of applying the given mapper to the elements of stream.
+// the mapper results are cached by input, so the mapper is called only once for each distinct element. T must be comparable to be used as the cache key.
+// Play: todo
+func MapMemoized[T comparable, R any](s Stream[T], mapper func(item T) R) Stream[R] {
+	source := make([]R, len(s.source))
+	cache := make(map[T]R)
+
+	for i, v := range s.source {
+		r, ok := cache[v]
+		if !ok {
+			r = mapper(v)
+			cache[v] = r
+		}
+		source[i] = r
+	}
+
+	return FromSlice(source)
+}
+
+// FlatMapSlice returns a stream consisting of the elements of slices which are the results of applying the given mapper to the elements of stream.
+// Play: todo
+func FlatMapSlice[T, R any](s Stream[T], mapper func(item T) []R) Stream[R] {
+	source := make([]R, 0)
+
+	for _, v := range s.source {
+		source = append(source, mapper(v)...)
+	}
+
+	return FromSlice(source)
+}
+
+// MapEachMapValue returns a stream of maps, whose values are the results of applying the given function to every value of each map element of stream.
+// Play: todo
+func MapEachMapValue[K comparable, V, R any](s Stream[map[K]V], fn func(value V) R) Stream[map[K]R] {
+	source := make([]map[K]R, len(s.source))
+
+	for i, m := range s.source {
+		result := make(map[K]R, len(m))
+		for k, v := range m {
+			result[k] = fn(v)
+		}
+		source[i] = result
+	}
+
+	return FromSlice(source)
+}
+
+// Flatten returns a stream consisting of the elements of all inner slices of stream in order.
+// Play: todo
+func Flatten[T any](s Stream[[]T]) Stream[T] {
+	l := 0
+	for _, v := range s.source {
+		l += len(v)
+	}
+
+	source := make([]T, 0, l)
+	for _, v := range s.source {
+		source = append(source, v...)
+	}
+
+	return FromSlice(source)
+}
+
+// MapPipeline returns a stream consisting of the elements of this stream that apply the given transforms in sequence, in a single pass.
+// Play: todo
+func (s Stream[T]) MapPipeline(transforms ...func(item T) T) Stream[T] {
+	source := make([]T, len(s.source))
+
+	for i, v := range s.source {
+		for _, transform := range transforms {
+			v = transform(v)
+		}
+		source[i] = v
+	}
+
+	return FromSlice(source)
+}
+
+// ParallelMapBounded returns a stream consisting of the results of applying the given mapper to the elements of stream in parallel, keeping the order of stream.
+// a goroutine is started for each element, but at most maxInFlight mappings run at the same time, which bounds the memory held by outstanding work.
+// Play: todo
+func ParallelMapBounded[T, R any](s Stream[T], mapper func(item T) R, maxInFlight int) Stream[R] {
+	if maxInFlight <= 0 {
+		panic("stream.ParallelMapBounded: param maxInFlight should be positive")
+	}
+
+	source := make([]R, len(s.source))
+	sem := semaphore.NewWeighted(int64(maxInFlight))
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i, v := range s.source {
+		// acquire never fails with background context.
+		_ = sem.Acquire(ctx, 1)
+
+		wg.Add(1)
+		go func(i int, item T) {
+			defer wg.Done()
+			defer sem.Release(1)
+
+			source[i] = mapper(item)
+		}(i, v)
+	}
+
+	wg.Wait()
+
+	return FromSlice(source)
+}
+
+// Peek returns a stream consisting of the elements of this stream, additionally performing the provided action on each element.
+// unlike java, the stream is eager, the action is performed on all elements immediately when Peek is called, before any later operation of the chain.
+// the returned stream holds a copy of the elements, so modifying it will not affect this stream.
+// Play: https://go.dev/play/p/u1VNzHs6cb2
+func (s Stream[T]) Peek(consumer func(item T)) Stream[T] {
+	source := make([]T, len(s.source))
+
+	for i, v := range s.source {
+		consumer(v)
+		source[i] = v
+	}
+
+	return FromSlice(source)
+}
+
+// Skip returns a stream consisting of the remaining elements of this stream after discarding the first n elements of the stream.
+// If this stream contains fewer than n elements then an empty stream will be returned.
+// Play: https://go.dev/play/p/fNdHbqjahum
+func (s Stream[T]) Skip(n int) Stream[T] {
+	if n <= 0 {
+		return s
+	}
+
+	if n > len(s.source) {
+		n = len(s.source)
+	}
+
+	source := make([]T, len(s.source)-n)
+	copy(source, s.source[n:])
+
+	return FromSlice(source)
+}
+
+// SkipWhile returns a stream consisting of the remaining elements of this stream after discarding the longest prefix of elements that match the given predicate.
+// Play: todo
+func (s Stream[T]) SkipWhile(predicate func(item T) bool) Stream[T] {
+	n := 0
+	for n < len(s.source) && predicate(s.source[n]) {
+		n++
+	}
+
+	source := make([]T, len(s.source)-n)
+	copy(source, s.source[n:])
+
+	return FromSlice(source)
+}
+
+// Trim returns a stream consisting of the elements of this stream after removing the leading and trailing elements which match shouldTrim.
+// the matched elements in the middle of stream are kept.
+// Play: todo
+func (s Stream[T]) Trim(shouldTrim func(item T) bool) Stream[T] {
+	start, end := 0, len(s.source)
+
+	for start < end && shouldTrim(s.source[start]) {
+		start++
+	}
+	for end > start && shouldTrim(s.source[end-1]) {
+		end--
+	}
+
+	source := make([]T, end-start)
+	copy(source, s.source[start:end])
+
+	return FromSlice(source)
+}
+
+// Limit returns a stream consisting of the elements of this stream, truncated to be no longer than maxSize in length.
+// Play: https://go.dev/play/p/qsO4aniDcGf
+func (s Stream[T]) Limit(maxSize int) Stream[T] {
+	if s.source == nil {
+		return s
+	}
+
+	if maxSize < 0 {
+		return FromSlice([]T{})
+	}
+
+	source := make([]T, 0, maxSize)
+
+	for i := 0; i < len(s.source) && i < maxSize; i++ {
+		source = append(source, s.source[i])
+	}
+
+	return FromSlice(source)
+}
+
+// LimitWhile returns a stream consisting of the longest prefix of elements of this stream that match the given predicate.
+// Play: todo
+func (s Stream[T]) LimitWhile(predicate func(item T) bool) Stream[T] {
+	n := 0
+	for n < len(s.source) && predicate(s.source[n]) {
+		n++
+	}
+
+	source := make([]T, n)
+	copy(source, s.source[:n])
+
+	return FromSlice(source)
+}
+
+// LimitSigned returns a stream consisting of the first n elements of this stream if n is not negative,
+// otherwise returns a stream consisting of all but the last |n| elements, like python slicing s[:n].
+// Play: todo
+func (s Stream[T]) LimitSigned(n int) Stream[T] {
+	l := len(s.source)
+
+	if n < 0 {
+		n += l
+		if n < 0 {
+			n = 0
+		}
+	} else if n > l {
+		n = l
+	}
+
+	source := make([]T, n)
+	copy(source, s.source[:n])
+
+	return FromSlice(source)
+}
+
+// TakeEvery returns a stream consisting of the elements at index offset, offset+n, offset+2n ... of this stream.
+// Play: todo
+func (s Stream[T]) TakeEvery(n, offset int) Stream[T] {
+	if n < 1 {
+		panic("stream.TakeEvery: param n should be positive")
+	} else if offset < 0 {
+		panic("stream.TakeEvery: param offset should not be negative")
+	}
+
+	source := make([]T, 0)
+
+	for i := offset; i < len(s.source); i += n {
+		source = append(source, s.source[i])
+	}
+
+	return FromSlice(source)
+}
+
+// SampleIndices returns k distinct random indices of the elements of this stream in ascending order, the randomness comes from rng.
+// Play: todo
+func (s Stream[T]) SampleIndices(k int, rng *rand.Rand) []int {
+	if k < 0 {
+		panic("stream.SampleIndices: param k should not be negative")
+	} else if k > len(s.source) {
+		panic("stream.SampleIndices: param k should not be greater than the count of elements")
+	}
+
+	result := rng.Perm(len(s.source))[:k]
+	sort.Ints(result)
+
+	return result
+}
+
+// AllMatch returns whether all elements of this stream match the provided predicate.
+// Play: https://go.dev/play/p/V5TBpVRs-Cx
+func (s Stream[T]) AllMatch(predicate func(item T) bool) bool {
+	for _, v := range s.source {
+		if !predicate(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AnyMatch returns whether any elements of this stream match the provided predicate.
+// Play: https://go.dev/play/p/PTCnWn4OxSn
+func (s Stream[T]) AnyMatch(predicate func(item T) bool) bool {
+	for _, v := range s.source {
+		if predicate(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NoneMatch returns whether no elements of this stream match the provided predicate.
+// Play: https://go.dev/play/p/iWS64pL1oo3
+func (s Stream[T]) NoneMatch(predicate func(item T) bool) bool {
+	return !s.AnyMatch(predicate)
+}
+
+// Contains returns whether the stream contains the target element, it stops at the first match.
+// Play: todo
+func Contains[T comparable](s Stream[T], target T) bool {
+	for _, v := range s.source {
+		if v == target {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ForEach performs an action for each element of this stream.
+// Play: https://go.dev/play/p/Dsm0fPqcidk
+func (s Stream[T]) ForEach(action func(item T)) {
+	for _, v := range s.source {
+		action(v)
+	}
+}
+
+// ForEachErr performs an action for each element of this stream.
+// it stops at the first error returned by action, and returns the error wrapped with the index of the element.
+// Play: todo
+func (s Stream[T]) ForEachErr(action func(item T) error) error {
+	for i, v := range s.source {
+		if err := action(v); err != nil {
+			return fmt.Errorf("stream.ForEachErr: element at index %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// ForEachErrElem performs an action for each element of this stream, it stops at the first error returned by action,
+// and returns the element which caused the error along with the error, or zero value and nil if all actions succeed.
+// Play: todo
+func (s Stream[T]) ForEachErrElem(action func(item T) error) (T, error) {
+	for _, v := range s.source {
+		if err := action(v); err != nil {
+			return v, err
+		}
+	}
+
+	var zeroValue T
+
+	return zeroValue, nil
+}
+
+// ForEachSampledErrors performs an action for each element of this stream, it keeps running after errors,
+// but only retains at most maxErrors of the errors in order of occurrence. the excess errors are counted but not stored,
+// the total count of errors is returned along with the retained errors.
+// Play: todo
+func (s Stream[T]) ForEachSampledErrors(action func(item T) error, maxErrors int) ([]error, int) {
+	errs := make([]error, 0)
+	count := 0
+
+	for _, v := range s.source {
+		if err := action(v); err != nil {
+			count++
+			if len(errs) < maxErrors {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errs, count
+}
+
+// ForEachContext performs an action for each element of this stream, it stops and returns the first error returned by action.
+// the context is checked before each element, if it is done, the iteration stops and returns ctx.Err().
+// Play: todo
+func (s Stream[T]) ForEachContext(ctx context.Context, action func(item T) error) error {
+	for _, v := range s.source {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := action(v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ForEachTracked performs an action for each element of this stream, and returns the minimum and maximum element according to less function (a < b) in the same pass.
+// ok is false if the stream is empty.
+// Play: todo
+func (s Stream[T]) ForEachTracked(action func(item T), less func(a, b T) bool) (min T, max T, ok bool) {
+	for i, v := range s.source {
+		action(v)
+
+		if i == 0 {
+			min, max = v, v
+			continue
+		}
+		if less(v, min) {
+			min = v
+		}
+		if less(max, v) {
+			max = v
+		}
+	}
+
+	return min, max, len(s.source) > 0
+}
+
+// ForEachControlled performs an action for each element of this stream, the iteration can be paused by sending true to pause channel, and resumed by sending false.
+// the pause channel is polled between elements without blocking, so an element in progress is not interrupted. closing the channel resumes the iteration.
+// Play: todo
+func (s Stream[T]) ForEachControlled(action func(item T), pause <-chan bool) {
+	for _, v := range s.source {
+		select {
+		case paused := <-pause:
+			for paused {
+				paused = <-pause
+			}
+		default:
+		}
+
+		action(v)
+	}
+}
+
+// ForEachReverse performs an action for each element of this stream in reverse order.
+// Play: todo
+func (s Stream[T]) ForEachReverse(action func(item T)) {
+	for i := len(s.source) - 1; i >= 0; i-- {
+		action(s.source[i])
+	}
+}
+
+// Route pairs a predicate with an action, used by Dispatch.
+// a route with nil Predicate matches every element, it can be used as the default route.
+type Route[T any] struct {
+	Predicate func(item T) bool
+	Action    func(item T)
+}
+
+// Dispatch sends each element of stream to the action of the first route whose predicate matches it.
+// the elements which match no route are dropped, put a route with nil Predicate at the end to handle them.
+// Play: todo
+func Dispatch[T any](s Stream[T], routes ...Route[T]) {
+	for _, v := range s.source {
+		for _, route := range routes {
+			if route.Predicate == nil || route.Predicate(v) {
+				route.Action(v)
+				break
+			}
+		}
+	}
+}
+
+// ShardForEach performs an action for each element of stream with the shard index computed by hasher(item) % shards,
+// so the same element is always routed to the same shard.
+// Play: todo
+func ShardForEach[T any](s Stream[T], shards int, hasher func(item T) uint64, action func(shard int, item T)) {
+	if shards <= 0 {
+		panic("stream.ShardForEach: param shards should be positive")
+	}
+
 	for _, v := range s.source {
-		consumer(v)
+		action(int(hasher(v)%uint64(shards)), v)
 	}
-
-	return s
 }
 
-// Skip returns a stream consisting of the remaining elements of this stream after discarding the first n elements of the stream.
-// If this stream contains fewer than n elements then an empty stream will be returned.
-// Play: https://go.dev/play/p/fNdHbqjahum
-func (s Stream[T]) Skip(n int) Stream[T] {
-	if n <= 0 {
-		return s
+// ParallelForEachOrderedComplete performs the action for each element of this stream in parallel with the given number of workers,
+// and calls onComplete with each element and the result of its action in the order of stream, in the calling goroutine.
+// the results which are completed out of order are buffered until all of the earlier ones are completed,
+// so at most Count() results may be buffered in the worst case.
+// Play: todo
+func (s Stream[T]) ParallelForEachOrderedComplete(action func(item T) any, onComplete func(item T, result any), workers int) {
+	if workers <= 0 {
+		panic("stream.ParallelForEachOrderedComplete: param workers should be positive")
 	}
 
-	source := make([]T, 0)
-	l := len(s.source)
-
-	if n > l {
-		return FromSlice(source)
+	type indexedResult struct {
+		index  int
+		result any
 	}
 
-	for i := n; i < l; i++ {
-		source = append(source, s.source[i])
+	indexes := make(chan int)
+	results := make(chan indexedResult, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results <- indexedResult{index: i, result: action(s.source[i])}
+			}
+		}()
 	}
 
-	return FromSlice(source)
+	go func() {
+		for i := range s.source {
+			indexes <- i
+		}
+		close(indexes)
+		wg.Wait()
+		close(results)
+	}()
+
+	pending := make(map[int]any)
+	next := 0
+	for r := range results {
+		pending[r.index] = r.result
+		for {
+			result, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			onComplete(s.source[next], result)
+			next++
+		}
+	}
 }
 
-// Limit returns a stream consisting of the elements of this stream, truncated to be no longer than maxSize in length.
-// Play: https://go.dev/play/p/qsO4aniDcGf
-func (s Stream[T]) Limit(maxSize int) Stream[T] {
-	if s.source == nil {
-		return s
+// ParallelForEachGroup performs the action for each element of this stream in parallel with at most workers goroutines, based on errgroup.
+// the context passed to action is cancelled at the first error, the remaining elements are not started, and the first error is returned.
+// if ctx is done before all elements are started, ctx.Err() is returned.
+// Play: todo
+func (s Stream[T]) ParallelForEachGroup(ctx context.Context, action func(ctx context.Context, item T) error, workers int) error {
+	if workers <= 0 {
+		panic("stream.ParallelForEachGroup: param workers should be positive")
 	}
 
-	if maxSize < 0 {
-		return FromSlice([]T{})
+	g, groupCtx := errgroup.WithContext(ctx)
+	g.SetLimit(workers)
+
+	stopped := false
+	for _, v := range s.source {
+		if groupCtx.Err() != nil {
+			stopped = true
+			break
+		}
+
+		item := v
+		g.Go(func() error {
+			return action(groupCtx, item)
+		})
 	}
 
-	source := make([]T, 0, maxSize)
+	if err := g.Wait(); err != nil {
+		return err
+	}
 
-	for i := 0; i < len(s.source) && i < maxSize; i++ {
-		source = append(source, s.source[i])
+	if stopped {
+		return ctx.Err()
 	}
 
-	return FromSlice(source)
+	return nil
 }
 
-// AllMatch returns whether all elements of this stream match the provided predicate.
-// Play: https://go.dev/play/p/V5TBpVRs-Cx
-func (s Stream[T]) AllMatch(predicate func(item T) bool) bool {
+// Reduce performs a reduction on the elements of this stream, using an associative accumulation function, and returns an Optional describing the reduced value, if any.
+// Play: https://go.dev/play/p/6uzZjq_DJLU
+func (s Stream[T]) Reduce(initial T, accumulator func(a, b T) T) T {
 	for _, v := range s.source {
-		if !predicate(v) {
-			return false
+		initial = accumulator(initial, v)
+	}
+
+	return initial
+}
+
+// CountAtMost returns the count of elements in the stream, but no more than max.
+// it stops counting once max is reached, so it can be used to check whether there are at least max elements.
+// Play: todo
+func (s Stream[T]) CountAtMost(max int) int {
+	if max <= 0 {
+		return 0
+	}
+
+	count := 0
+	for range s.source {
+		count++
+		if count == max {
+			break
 		}
 	}
 
-	return true
+	return count
 }
 
-// AnyMatch returns whether any elements of this stream match the provided predicate.
-// Play: https://go.dev/play/p/PTCnWn4OxSn
-func (s Stream[T]) AnyMatch(predicate func(item T) bool) bool {
+// CountBy returns the count of elements in the stream which match the provided predicate.
+// Play: todo
+func (s Stream[T]) CountBy(predicate func(item T) bool) int {
+	count := 0
+
 	for _, v := range s.source {
 		if predicate(v) {
-			return true
+			count++
 		}
 	}
 
-	return false
+	return count
 }
 
-// NoneMatch returns whether no elements of this stream match the provided predicate.
-// Play: https://go.dev/play/p/iWS64pL1oo3
-func (s Stream[T]) NoneMatch(predicate func(item T) bool) bool {
-	return !s.AnyMatch(predicate)
+// JoinToString concatenates the string form of elements of stream, separated by sep.
+// Play: todo
+func JoinToString[T any](s Stream[T], sep string, stringify func(item T) string) string {
+	var builder strings.Builder
+
+	for i, v := range s.source {
+		if i > 0 {
+			builder.WriteString(sep)
+		}
+		builder.WriteString(stringify(v))
+	}
+
+	return builder.String()
 }
 
-// ForEach performs an action for each element of this stream.
-// Play: https://go.dev/play/p/Dsm0fPqcidk
-func (s Stream[T]) ForEach(action func(item T)) {
-	for _, v := range s.source {
-		action(v)
+// Join concatenates the elements of string stream, separated by sep.
+// Play: todo
+func Join(s Stream[string], sep string) string {
+	return strings.Join(s.source, sep)
+}
+
+// Monoid is an identity value with an associative combine function, it defines a reusable reduction.
+type Monoid[T any] struct {
+	Identity T
+	Combine  func(a, b T) T
+}
+
+// SumMonoid returns a monoid which sums numbers.
+// Play: todo
+func SumMonoid[T constraints.Integer | constraints.Float]() Monoid[T] {
+	return Monoid[T]{
+		Identity: 0,
+		Combine:  func(a, b T) T { return a + b },
 	}
 }
 
-// Reduce performs a reduction on the elements of this stream, using an associative accumulation function, and returns an Optional describing the reduced value, if any.
-// Play: https://go.dev/play/p/6uzZjq_DJLU
-func (s Stream[T]) Reduce(initial T, accumulator func(a, b T) T) T {
-	for _, v := range s.source {
-		initial = accumulator(initial, v)
+// ConcatMonoid returns a monoid which concatenates strings.
+// Play: todo
+func ConcatMonoid() Monoid[string] {
+	return Monoid[string]{
+		Identity: "",
+		Combine:  func(a, b string) string { return a + b },
 	}
+}
 
-	return initial
+// ReduceMonoid performs a reduction on the elements of this stream with the monoid, returns the identity of monoid if the stream is empty.
+// Play: todo
+func ReduceMonoid[T any](s Stream[T], m Monoid[T]) T {
+	return s.Reduce(m.Identity, m.Combine)
 }
 
 // Count returns the count of elements in the stream.
@@ -307,6 +1279,32 @@ func (s Stream[T]) FindLast() (T, bool) {
 	return s.source[len(s.source)-1], true
 }
 
+// Find returns the first element of this stream which matches the predicate and true, or zero value and false if no element matches.
+// Play: todo
+func (s Stream[T]) Find(predicate func(item T) bool) (T, bool) {
+	for _, v := range s.source {
+		if predicate(v) {
+			return v, true
+		}
+	}
+
+	var result T
+
+	return result, false
+}
+
+// ElementAt returns the element at the index (zero-based) of this stream and true, or zero value and false if the index is out of range.
+// Play: todo
+func (s Stream[T]) ElementAt(index int) (T, bool) {
+	var result T
+
+	if index < 0 || index >= len(s.source) {
+		return result, false
+	}
+
+	return s.source[index], true
+}
+
 // Reverse returns a stream whose elements are reverse order of given stream.
 // Play: https://go.dev/play/p/A8_zkJnLHm4
 func (s Stream[T]) Reverse() Stream[T] {
@@ -345,6 +1343,68 @@ func (s Stream[T]) Range(start, end int) Stream[T] {
 	return FromSlice(source)
 }
 
+// Window returns a stream of sliding windows of the elements of stream, each window has size elements and starts step elements after the previous one.
+// the windows which would run past the end of stream are dropped, so the stream which has fewer than size elements produces an empty stream.
+// Play: todo
+func Window[T any](s Stream[T], size, step int) Stream[[]T] {
+	if size <= 0 {
+		panic("stream.Window: param size should be positive")
+	} else if step <= 0 {
+		panic("stream.Window: param step should be positive")
+	}
+
+	source := make([][]T, 0)
+
+	for i := 0; i+size <= len(s.source); i += step {
+		window := make([]T, size)
+		copy(window, s.source[i:i+size])
+		source = append(source, window)
+	}
+
+	return FromSlice(source)
+}
+
+// ChunkPadded returns a stream of chunks of the elements of stream, each chunk has exactly size elements,
+// the last short chunk is padded with pad up to size.
+// Play: todo
+func ChunkPadded[T any](s Stream[T], size int, pad T) Stream[[]T] {
+	if size <= 0 {
+		panic("stream.ChunkPadded: param size should be positive")
+	}
+
+	source := make([][]T, 0, (len(s.source)+size-1)/size)
+
+	for i := 0; i < len(s.source); i += size {
+		chunk := make([]T, size)
+		n := copy(chunk, s.source[i:])
+		for j := n; j < size; j++ {
+			chunk[j] = pad
+		}
+		source = append(source, chunk)
+	}
+
+	return FromSlice(source)
+}
+
+// SessionWindow groups the consecutive elements of stream into sessions, in which sameSession holds between every two neighbors,
+// and returns a stream consisting of the results of applying reducer to each session.
+// Play: todo
+func SessionWindow[T any](s Stream[T], sameSession func(prev, curr T) bool, reducer func(session []T) T) Stream[T] {
+	source := make([]T, 0)
+
+	start := 0
+	for i := 1; i <= len(s.source); i++ {
+		if i == len(s.source) || !sameSession(s.source[i-1], s.source[i]) {
+			session := make([]T, i-start)
+			copy(session, s.source[start:i])
+			source = append(source, reducer(session))
+			start = i
+		}
+	}
+
+	return FromSlice(source)
+}
+
 // Sorted returns a stream consisting of the elements of this stream, sorted according to the provided less function.
 // Play: https://go.dev/play/p/XXtng5uonFj
 func (s Stream[T]) Sorted(less func(a, b T) bool) Stream[T] {
@@ -356,6 +1416,60 @@ func (s Stream[T]) Sorted(less func(a, b T) bool) Stream[T] {
 	return FromSlice(source)
 }
 
+// Asc returns a less function which compares the elements in ascending order.
+// Play: todo
+func Asc[T constraints.Ordered]() func(a, b T) bool {
+	return func(a, b T) bool {
+		return a < b
+	}
+}
+
+// Desc returns a less function which compares the elements in descending order.
+// Play: todo
+func Desc[T constraints.Ordered]() func(a, b T) bool {
+	return func(a, b T) bool {
+		return a > b
+	}
+}
+
+// ByKey returns a less function which compares the elements by the key derived from keyFn in ascending order.
+// Play: todo
+func ByKey[T any, K constraints.Ordered](keyFn func(item T) K) func(a, b T) bool {
+	return func(a, b T) bool {
+		return keyFn(a) < keyFn(b)
+	}
+}
+
+// TopN returns a stream consisting of the n greatest elements of this stream according to the provided less function, sorted in ascending order.
+// it keeps a bounded heap of n elements instead of sorting the whole stream. if n >= Count(), it behaves like Sorted.
+// Play: todo
+func TopN[T any](s Stream[T], n int, less func(a, b T) bool) Stream[T] {
+	if n <= 0 {
+		return Empty[T]()
+	}
+	if n > len(s.source) {
+		n = len(s.source)
+	}
+
+	h := &lessHeap[T]{data: make([]T, 0, n), less: less}
+
+	for _, v := range s.source {
+		if h.Len() < n {
+			heap.Push(h, v)
+		} else if less(h.data[0], v) {
+			h.data[0] = v
+			heap.Fix(h, 0)
+		}
+	}
+
+	source := make([]T, n)
+	for i := 0; i < n; i++ {
+		source[i] = heap.Pop(h).(T)
+	}
+
+	return FromSlice(source)
+}
+
 // Max returns the maximum element of this stream according to the provided less function.
 // less: a > b
 // Play: https://go.dev/play/p/fm-1KOPtGzn
@@ -393,8 +1507,297 @@ func (s Stream[T]) Min(less func(a, b T) bool) (T, bool) {
 	return min, true
 }
 
+// MinMax returns the minimum and maximum element of this stream according to the provided less function in a single pass.
+// ok is false if the stream is empty.
+// less: a < b
+// Play: todo
+func (s Stream[T]) MinMax(less func(a, b T) bool) (min T, max T, ok bool) {
+	return s.ForEachTracked(func(item T) {}, less)
+}
+
+// ArgMinMax returns the index of the minimum and maximum element of stream in a single pass, the first index is returned for ties.
+// ok is false if the stream is empty.
+// Play: todo
+func ArgMinMax[T constraints.Ordered](s Stream[T]) (minIdx, maxIdx int, ok bool) {
+	if len(s.source) == 0 {
+		return 0, 0, false
+	}
+
+	for i, v := range s.source {
+		if v < s.source[minIdx] {
+			minIdx = i
+		}
+		if v > s.source[maxIdx] {
+			maxIdx = i
+		}
+	}
+
+	return minIdx, maxIdx, true
+}
+
 // ToSlice return the elements in the stream.
 // Play: https://go.dev/play/p/jI6_iZZuVFE
 func (s Stream[T]) ToSlice() []T {
 	return s.source
 }
+
+// WriteCSV writes each element of stream as a csv record to w, the record is converted from element by the row function.
+// it flushes the data at the end and returns the first write error.
+// Play: todo
+func (s Stream[T]) WriteCSV(w io.Writer, row func(item T) []string) error {
+	writer := csv.NewWriter(w)
+
+	for _, v := range s.source {
+		if err := writer.Write(row(v)); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// Iterator returns a pull function which yields one element of stream per call in order, and zero value and false when the stream is exhausted.
+// Play: todo
+func (s Stream[T]) Iterator() func() (T, bool) {
+	index := 0
+
+	return func() (T, bool) {
+		var zeroValue T
+
+		if index >= len(s.source) {
+			return zeroValue, false
+		}
+
+		item := s.source[index]
+		index++
+
+		return item, true
+	}
+}
+
+// Backward returns a push iterator which yields the elements of stream from last to first without allocating a reversed slice,
+// the iteration stops when yield returns false. it has the same signature as iter.Seq[T], so it can be ranged over in go1.23+.
+// Play: todo
+func (s Stream[T]) Backward() func(yield func(item T) bool) {
+	return func(yield func(item T) bool) {
+		for i := len(s.source) - 1; i >= 0; i-- {
+			if !yield(s.source[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Tee returns two independent streams which both contain a copy of the elements of this stream.
+// Play: todo
+func (s Stream[T]) Tee() (Stream[T], Stream[T]) {
+	a := make([]T, len(s.source))
+	b := make([]T, len(s.source))
+
+	copy(a, s.source)
+	copy(b, s.source)
+
+	return FromSlice(a), FromSlice(b)
+}
+
+// ToSliceAndIndex returns the elements in the stream, and a map from the key computed by keyer to the index of element in the slice.
+// if keys collide, the index of the last element wins.
+// Play: todo
+func ToSliceAndIndex[T any, K comparable](s Stream[T], keyer func(item T) K) ([]T, map[K]int) {
+	result := make([]T, len(s.source))
+	index := make(map[K]int, len(s.source))
+
+	for i, v := range s.source {
+		result[i] = v
+		index[keyer(v)] = i
+	}
+
+	return result, index
+}
+
+// ToHeap returns a min heap according to the less function, which is initialized with the elements of stream in O(n).
+// Play: todo
+func (s Stream[T]) ToHeap(less func(a, b T) bool) *Heap[T] {
+	data := make([]T, len(s.source))
+	copy(data, s.source)
+
+	h := &lessHeap[T]{data: data, less: less}
+	heap.Init(h)
+
+	return &Heap[T]{h: h}
+}
+
+// ToSet returns a set (map with empty struct value) of the distinct elements in the stream.
+// Play: todo
+func ToSet[T comparable](s Stream[T]) map[T]struct{} {
+	result := make(map[T]struct{}, len(s.source))
+
+	for _, v := range s.source {
+		result[v] = struct{}{}
+	}
+
+	return result
+}
+
+// GroupByCount returns the count of elements for each key computed by keyFn.
+// Play: todo
+func GroupByCount[T any, K comparable](s Stream[T], keyFn func(item T) K) map[K]int {
+	result := make(map[K]int)
+
+	for _, v := range s.source {
+		result[keyFn(v)]++
+	}
+
+	return result
+}
+
+// SortedCountBy returns the count of elements for each key computed by keyer, the key/count pairs are sorted by key in ascending order.
+// Play: todo
+func SortedCountBy[T any, K constraints.Ordered](s Stream[T], keyer func(item T) K) []Pair[K, int] {
+	counts := GroupByCount(s, keyer)
+
+	return FromMapSorted(counts, func(a, b K) bool { return a < b }).source
+}
+
+// ToOrderedMap returns an OrderedMap whose keys and values are computed by keyer and valuer from elements of stream, keys keep the order of stream.
+// if keys collide, the last value wins but the key keeps its first position.
+// Play: todo
+func ToOrderedMap[T any, K comparable, V any](s Stream[T], keyer func(item T) K, valuer func(item T) V) *OrderedMap[K, V] {
+	result := NewOrderedMap[K, V]()
+
+	for _, v := range s.source {
+		result.Set(keyer(v), valuer(v))
+	}
+
+	return result
+}
+
+// PartitionByWeight partitions the elements of stream into groups buckets, balancing the total weight of each bucket.
+// it uses the greedy heuristic: the elements are assigned in descending order of weight, each to the bucket with the least total weight so far.
+// the result is not guaranteed to be optimal. the elements in each bucket keep the order of stream.
+// Play: todo
+func PartitionByWeight[T any](s Stream[T], groups int, weight func(item T) float64) [][]T {
+	if groups <= 0 {
+		panic("stream.PartitionByWeight: param groups should be positive")
+	}
+
+	l := len(s.source)
+
+	weights := make([]float64, l)
+	indexes := make([]int, l)
+	for i, v := range s.source {
+		weights[i] = weight(v)
+		indexes[i] = i
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return weights[indexes[i]] > weights[indexes[j]]
+	})
+
+	totals := make([]float64, groups)
+	assigned := make([]int, l)
+	for _, index := range indexes {
+		lightest := 0
+		for g := 1; g < groups; g++ {
+			if totals[g] < totals[lightest] {
+				lightest = g
+			}
+		}
+		totals[lightest] += weights[index]
+		assigned[index] = lightest
+	}
+
+	result := make([][]T, groups)
+	for g := range result {
+		result[g] = make([]T, 0)
+	}
+	for i, v := range s.source {
+		result[assigned[i]] = append(result[assigned[i]], v)
+	}
+
+	return result
+}
+
+// GroupByWindow buckets the elements of stream into fixed width time windows, each window is keyed by its start time (timestamp truncated to window).
+// the stream does not need to be time ordered, since the elements are stably sorted by timestamp internally.
+// the windows are in ascending time order, empty windows are not emitted.
+// Play: todo
+func GroupByWindow[T any](s Stream[T], timestamp func(item T) time.Time, window time.Duration) Stream[Pair[time.Time, []T]] {
+	if window <= 0 {
+		panic("stream.GroupByWindow: param window should be positive")
+	}
+
+	items := make([]T, len(s.source))
+	copy(items, s.source)
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return timestamp(items[i]).Before(timestamp(items[j]))
+	})
+
+	source := make([]Pair[time.Time, []T], 0)
+	for _, v := range items {
+		start := timestamp(v).Truncate(window)
+
+		if l := len(source); l > 0 && source[l-1].Key.Equal(start) {
+			source[l-1].Val = append(source[l-1].Val, v)
+		} else {
+			source = append(source, Pair[time.Time, []T]{Key: start, Val: []T{v}})
+		}
+	}
+
+	return FromSlice(source)
+}
+
+// Bucketize sorts a copy of the elements of stream in ascending order, and splits them into n quantile buckets,
+// the sizes of buckets differ by at most one, the leading buckets take one more element when the count is not divisible by n.
+// if n is greater than the count of elements, each of the leading buckets holds one element and the rest are empty.
+// Play: todo
+func Bucketize[T constraints.Ordered](s Stream[T], n int) [][]T {
+	if n < 1 {
+		panic("stream.Bucketize: param n should be positive")
+	}
+
+	sorted := s.Sorted(Asc[T]()).source
+	size, remainder := len(sorted)/n, len(sorted)%n
+
+	result := make([][]T, n)
+	start := 0
+	for i := 0; i < n; i++ {
+		end := start + size
+		if i < remainder {
+			end++
+		}
+		result[i] = sorted[start:end:end]
+		start = end
+	}
+
+	return result
+}
+
+// Diff3 compares two streams as sets, and returns the distinct elements only in newStream (added), only in oldStream (removed), and in both (common).
+// added is in first seen order of newStream, removed and common are in first seen order of oldStream.
+// Play: todo
+func Diff3[T comparable](oldStream, newStream Stream[T]) (added []T, removed []T, common []T) {
+	oldSet := ToSet(oldStream)
+	newSet := ToSet(newStream)
+
+	added = FilterDistinct(newStream, func(item T) bool {
+		_, ok := oldSet[item]
+		return !ok
+	}).source
+
+	removed = make([]T, 0)
+	common = make([]T, 0)
+
+	for _, v := range ConcatDistinct(oldStream).source {
+		if _, ok := newSet[v]; ok {
+			common = append(common, v)
+		} else {
+			removed = append(removed, v)
+		}
+	}
+
+	return added, removed, common
+}
@@ -1,7 +1,16 @@
 package stream
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
 func ExampleOf() {
@@ -15,6 +24,17 @@ func ExampleOf() {
 	// [1 2 3]
 }
 
+func ExampleOfLimited() {
+	s := OfLimited(3, 1, 2, 3, 4, 5)
+
+	data := s.ToSlice()
+
+	fmt.Println(data)
+
+	// Output:
+	// [1 2 3]
+}
+
 func ExampleFromSlice() {
 	s := FromSlice([]int{1, 2, 3})
 
@@ -26,6 +46,30 @@ func ExampleFromSlice() {
 	// [1 2 3]
 }
 
+func ExampleFromSliceOwned() {
+	source := []int{1, 2, 3}
+
+	s := FromSliceOwned(source)
+	s.ToSlice()[0] = 100
+
+	fmt.Println(source)
+
+	// Output:
+	// [100 2 3]
+}
+
+func ExampleFromSliceCopy() {
+	source := []int{1, 2, 3}
+
+	s := FromSliceCopy(source)
+	source[0] = 100
+
+	fmt.Println(s.ToSlice())
+
+	// Output:
+	// [1 2 3]
+}
+
 func ExampleFromChannel() {
 	ch := make(chan int)
 	go func() {
@@ -45,6 +89,51 @@ func ExampleFromChannel() {
 	// [1 2 3]
 }
 
+func ExampleFromMap() {
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+
+	s := FromMap(m)
+
+	sum := s.Reduce(Pair[string, int]{}, func(a, b Pair[string, int]) Pair[string, int] {
+		return Pair[string, int]{Val: a.Val + b.Val}
+	})
+
+	fmt.Println(s.Count())
+	fmt.Println(sum.Val)
+
+	// Output:
+	// 3
+	// 6
+}
+
+func ExampleFromMapSorted() {
+	m := map[string]int{"c": 3, "a": 1, "b": 2}
+
+	s := FromMapSorted(m, func(a, b string) bool { return a < b })
+
+	data := s.ToSlice()
+
+	fmt.Println(data)
+
+	// Output:
+	// [{a 1} {b 2} {c 3}]
+}
+
+func ExampleFromCSV() {
+	data := "Tom,10\nJim,20\n"
+
+	s, err := FromCSV(strings.NewReader(data), func(record []string) (string, error) {
+		return record[0] + ":" + record[1], nil
+	})
+
+	fmt.Println(s.ToSlice())
+	fmt.Println(err)
+
+	// Output:
+	// [Tom:10 Jim:20]
+	// <nil>
+}
+
 func ExampleFromRange() {
 	s := FromRange(1, 5, 1)
 
@@ -55,6 +144,57 @@ func ExampleFromRange() {
 	// [1 2 3 4 5]
 }
 
+func ExampleEmpty() {
+	s := Empty[int]()
+
+	fmt.Println(s.Count())
+
+	// Output:
+	// 0
+}
+
+func ExampleRepeat() {
+	s := Repeat("a", 3)
+
+	data := s.ToSlice()
+	fmt.Println(data)
+
+	// Output:
+	// [a a a]
+}
+
+func ExampleFromNonZero() {
+	s := FromNonZero("a", "", "b", "")
+
+	data := s.ToSlice()
+
+	fmt.Println(data)
+
+	// Output:
+	// [a b]
+}
+
+func ExampleFromRangeN() {
+	s := FromRangeN(1, 2, 4)
+
+	data := s.ToSlice()
+	fmt.Println(data)
+
+	// Output:
+	// [1 3 5 7]
+}
+
+func ExampleOfSlices() {
+	s := OfSlices([]int{1, 2}, nil, []int{3})
+
+	data := s.ToSlice()
+
+	fmt.Println(data)
+
+	// Output:
+	// [1 2 3]
+}
+
 func ExampleGenerate() {
 	n := 0
 	max := 4
@@ -76,6 +216,16 @@ func ExampleGenerate() {
 	// [1 2 3]
 }
 
+func ExampleCycle() {
+	s := Cycle([]int{1, 2}, 3)
+
+	data := s.ToSlice()
+	fmt.Println(data)
+
+	// Output:
+	// [1 2 1 2 1 2]
+}
+
 func ExampleConcat() {
 	s1 := FromSlice([]int{1, 2, 3})
 	s2 := FromSlice([]int{4, 5, 6})
@@ -90,6 +240,18 @@ func ExampleConcat() {
 	// [1 2 3 4 5 6]
 }
 
+func ExampleConcatDistinct() {
+	s1 := FromSlice([]int{1, 2, 3})
+	s2 := FromSlice([]int{3, 4, 1})
+
+	s := ConcatDistinct(s1, s2)
+
+	fmt.Println(s.ToSlice())
+
+	// Output:
+	// [1 2 3 4]
+}
+
 func ExampleStream_Distinct() {
 	original := FromSlice([]int{1, 2, 2, 3, 3, 3})
 	distinct := original.Distinct()
@@ -105,6 +267,67 @@ func ExampleStream_Distinct() {
 	// [1 2 3]
 }
 
+func ExampleDistinctSortedAssumeSorted() {
+	original := FromSlice([]int{1, 1, 2, 3, 3, 3})
+
+	distinct := DistinctSortedAssumeSorted(original)
+
+	fmt.Println(distinct.ToSlice())
+
+	// Output:
+	// [1 2 3]
+}
+
+func ExampleDistinctByWithKeys() {
+	original := FromSlice([]string{"a1", "b1", "a2", "c1", "b2"})
+
+	result, keys := DistinctByWithKeys(original, func(item string) string {
+		return item[:1]
+	})
+
+	fmt.Println(result.ToSlice())
+	fmt.Println(keys)
+
+	// Output:
+	// [a1 b1 c1]
+	// [a b c]
+}
+
+func ExampleDistinctByKeepLatest() {
+	original := FromSlice([]string{"a1", "b1", "a2", "c1", "b2"})
+
+	result := DistinctByKeepLatest(original, func(item string) byte {
+		return item[0]
+	})
+
+	fmt.Println(result.ToSlice())
+
+	// Output:
+	// [a2 c1 b2]
+}
+
+func ExampleDedupConsecutive() {
+	original := FromSlice([]int{1, 1, 2, 2, 1})
+
+	result := DedupConsecutive(original)
+
+	fmt.Println(result.ToSlice())
+
+	// Output:
+	// [1 2 1]
+}
+
+func ExampleStream_DistinctDeep() {
+	original := FromSlice([][]int{{1, 2}, {3}, {1, 2}})
+
+	distinct := original.DistinctDeep()
+
+	fmt.Println(distinct.ToSlice())
+
+	// Output:
+	// [[1 2] [3]]
+}
+
 func ExampleStream_Filter() {
 	original := FromSlice([]int{1, 2, 3, 4, 5})
 
@@ -120,6 +343,40 @@ func ExampleStream_Filter() {
 	// [2 4]
 }
 
+func ExampleFilterDistinct() {
+	original := FromSlice([]int{4, 1, 2, 4, 3, 2})
+
+	even := FilterDistinct(original, func(item int) bool {
+		return item%2 == 0
+	})
+
+	fmt.Println(even.ToSlice())
+
+	// Output:
+	// [4 2]
+}
+
+func ExampleStream_FilterContext() {
+	original := FromSlice([]int{1, 2, 3, 4, 5, 6})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	even, err := original.FilterContext(ctx, func(item int) bool {
+		if item == 4 {
+			cancel()
+		}
+		return item%2 == 0
+	})
+
+	fmt.Println(even.ToSlice())
+	fmt.Println(err)
+
+	// Output:
+	// [2 4]
+	// context canceled
+}
+
 func ExampleStream_Map() {
 	original := FromSlice([]int{1, 2, 3})
 
@@ -135,6 +392,130 @@ func ExampleStream_Map() {
 	// [2 3 4]
 }
 
+func ExampleFilterMap() {
+	original := FromSlice([]string{"1", "a", "2", "b", "3"})
+
+	nums := FilterMap(original, func(item string) (int, bool) {
+		n, err := strconv.Atoi(item)
+		return n, err == nil
+	})
+
+	fmt.Println(nums.ToSlice())
+
+	// Output:
+	// [1 2 3]
+}
+
+func ExampleMapUntilError() {
+	original := FromSlice([]string{"1", "2", "a", "4"})
+
+	nums, err := MapUntilError(original, strconv.Atoi)
+
+	fmt.Println(nums.ToSlice())
+	fmt.Println(err != nil)
+
+	// Output:
+	// [1 2]
+	// true
+}
+
+func ExampleMapErr() {
+	original := FromSlice([]string{"1", "2", "a"})
+
+	nums, err := MapErr(original, strconv.Atoi)
+
+	fmt.Println(nums.ToSlice())
+	fmt.Println(err)
+
+	// Output:
+	// []
+	// stream.MapErr: element at index 2: strconv.Atoi: parsing "a": invalid syntax
+}
+
+func ExampleMapMemoized() {
+	original := FromSlice([]int{1, 2, 1, 2})
+
+	calls := 0
+	result := MapMemoized(original, func(item int) int {
+		calls++
+		return item * 10
+	})
+
+	fmt.Println(result.ToSlice())
+	fmt.Println(calls)
+
+	// Output:
+	// [10 20 10 20]
+	// 2
+}
+
+func ExampleFlatMapSlice() {
+	original := FromSlice([]int{1, 2, 3})
+
+	result := FlatMapSlice(original, func(item int) []int {
+		return Repeat(item, item).ToSlice()
+	})
+
+	fmt.Println(result.ToSlice())
+
+	// Output:
+	// [1 2 2 3 3 3]
+}
+
+func ExampleMapEachMapValue() {
+	original := FromSlice([]map[string]int{
+		{"a": 1, "b": 2},
+		{"c": 3},
+	})
+
+	result := MapEachMapValue(original, func(value int) string {
+		return strconv.Itoa(value * 2)
+	})
+
+	fmt.Println(result.ToSlice())
+
+	// Output:
+	// [map[a:2 b:4] map[c:6]]
+}
+
+func ExampleFlatten() {
+	original := FromSlice([][]int{{1, 2}, nil, {3}})
+
+	result := Flatten(original)
+
+	fmt.Println(result.ToSlice())
+
+	// Output:
+	// [1 2 3]
+}
+
+func ExampleStream_MapPipeline() {
+	original := FromSlice([]int{1, 2, 3})
+
+	s := original.MapPipeline(
+		func(item int) int { return item + 1 },
+		func(item int) int { return item * 2 },
+	)
+
+	fmt.Println(s.ToSlice())
+
+	// Output:
+	// [4 6 8]
+}
+
+func ExampleParallelMapBounded() {
+	original := FromSlice([]int{1, 2, 3, 4, 5})
+
+	result := ParallelMapBounded(original, func(item int) string {
+		return strconv.Itoa(item * 10)
+	}, 2)
+
+	fmt.Println(result.ToSlice())
+
+	// Output:
+	// [10 20 30 40 50]
+}
+
 func ExampleStream_Peek() {
 	original := FromSlice([]int{1, 2, 3})
 
@@ -173,6 +554,32 @@ func ExampleStream_Skip() {
 	// []
 }
 
+func ExampleStream_SkipWhile() {
+	original := FromSlice([]int{1, 2, 3, 4, 1})
+
+	s := original.SkipWhile(func(item int) bool {
+		return item < 3
+	})
+
+	fmt.Println(s.ToSlice())
+
+	// Output:
+	// [3 4 1]
+}
+
+func ExampleStream_Trim() {
+	original := FromSlice([]int{0, 0, 1, 0, 2, 0, 0})
+
+	s := original.Trim(func(item int) bool {
+		return item == 0
+	})
+
+	fmt.Println(s.ToSlice())
+
+	// Output:
+	// [1 0 2]
+}
+
 func ExampleStream_Limit() {
 	original := FromSlice([]int{1, 2, 3, 4})
 
@@ -193,35 +600,88 @@ func ExampleStream_Limit() {
 	// [1 2 3 4]
 }
 
-func ExampleStream_AllMatch() {
-	original := FromSlice([]int{1, 2, 3})
-
-	result1 := original.AllMatch(func(item int) bool {
-		return item > 0
-	})
+func ExampleStream_LimitWhile() {
+	original := FromSlice([]int{1, 2, 3, 4, 1})
 
-	result2 := original.AllMatch(func(item int) bool {
-		return item > 1
+	s := original.LimitWhile(func(item int) bool {
+		return item < 3
 	})
 
-	fmt.Println(result1)
-	fmt.Println(result2)
+	fmt.Println(s.ToSlice())
 
 	// Output:
-	// true
-	// false
+	// [1 2]
 }
 
-func ExampleStream_AnyMatch() {
-	original := FromSlice([]int{1, 2, 3})
+func ExampleStream_LimitSigned() {
+	original := FromSlice([]int{1, 2, 3, 4, 5})
 
-	result1 := original.AnyMatch(func(item int) bool {
-		return item > 1
-	})
+	s1 := original.LimitSigned(2)
+	s2 := original.LimitSigned(-2)
 
-	result2 := original.AnyMatch(func(item int) bool {
-		return item > 3
-	})
+	fmt.Println(s1.ToSlice())
+	fmt.Println(s2.ToSlice())
+
+	// Output:
+	// [1 2]
+	// [1 2 3]
+}
+
+func ExampleStream_TakeEvery() {
+	original := FromRange(0, 9, 1)
+
+	s := original.TakeEvery(3, 1)
+
+	fmt.Println(s.ToSlice())
+
+	// Output:
+	// [1 4 7]
+}
+
+func ExampleStream_SampleIndices() {
+	original := FromRange(0, 9, 1)
+
+	indices := original.SampleIndices(3, rand.New(rand.NewSource(42)))
+
+	fmt.Println(len(indices))
+	fmt.Println(sort.IntsAreSorted(indices))
+	fmt.Println(len(ToSet(FromSlice(indices))))
+
+	// Output:
+	// 3
+	// true
+	// 3
+}
+
+func ExampleStream_AllMatch() {
+	original := FromSlice([]int{1, 2, 3})
+
+	result1 := original.AllMatch(func(item int) bool {
+		return item > 0
+	})
+
+	result2 := original.AllMatch(func(item int) bool {
+		return item > 1
+	})
+
+	fmt.Println(result1)
+	fmt.Println(result2)
+
+	// Output:
+	// true
+	// false
+}
+
+func ExampleStream_AnyMatch() {
+	original := FromSlice([]int{1, 2, 3})
+
+	result1 := original.AnyMatch(func(item int) bool {
+		return item > 1
+	})
+
+	result2 := original.AnyMatch(func(item int) bool {
+		return item > 3
+	})
 
 	fmt.Println(result1)
 	fmt.Println(result2)
@@ -250,6 +710,20 @@ func ExampleStream_NoneMatch() {
 	// false
 }
 
+func ExampleContains() {
+	original := FromSlice([]int{1, 2, 3})
+
+	result1 := Contains(original, 1)
+	result2 := Contains(original, 4)
+
+	fmt.Println(result1)
+	fmt.Println(result2)
+
+	// Output:
+	// true
+	// false
+}
+
 func ExampleStream_ForEach() {
 	original := FromSlice([]int{1, 2, 3})
 
@@ -264,6 +738,199 @@ func ExampleStream_ForEach() {
 	// 6
 }
 
+func ExampleStream_ForEachErr() {
+	original := FromSlice([]int{1, 2, 3})
+
+	err := original.ForEachErr(func(item int) error {
+		if item == 2 {
+			return errors.New("invalid item")
+		}
+		fmt.Println(item)
+		return nil
+	})
+
+	fmt.Println(err)
+
+	// Output:
+	// 1
+	// stream.ForEachErr: element at index 1: invalid item
+}
+
+func ExampleStream_ForEachErrElem() {
+	original := FromSlice([]string{"1", "2", "a", "b"})
+
+	item, err := original.ForEachErrElem(func(item string) error {
+		_, err := strconv.Atoi(item)
+		return err
+	})
+
+	fmt.Println(item)
+	fmt.Println(err)
+
+	// Output:
+	// a
+	// strconv.Atoi: parsing "a": invalid syntax
+}
+
+func ExampleStream_ForEachSampledErrors() {
+	original := FromRange(1, 6, 1)
+
+	errs, count := original.ForEachSampledErrors(func(item int) error {
+		if item%2 == 0 {
+			return fmt.Errorf("error at %d", item)
+		}
+		return nil
+	}, 2)
+
+	fmt.Println(errs)
+	fmt.Println(count)
+
+	// Output:
+	// [error at 2 error at 4]
+	// 3
+}
+
+func ExampleStream_ForEachContext() {
+	original := FromSlice([]int{1, 2, 3})
+
+	err := original.ForEachContext(context.Background(), func(item int) error {
+		fmt.Println(item)
+		return nil
+	})
+
+	fmt.Println(err)
+
+	// Output:
+	// 1
+	// 2
+	// 3
+	// <nil>
+}
+
+func ExampleStream_ForEachTracked() {
+	original := FromSlice([]int{4, 2, 5, 1, 3})
+
+	sum := 0
+	min, max, ok := original.ForEachTracked(func(item int) {
+		sum += item
+	}, func(a, b int) bool { return a < b })
+
+	fmt.Println(sum)
+	fmt.Println(min)
+	fmt.Println(max)
+	fmt.Println(ok)
+
+	// Output:
+	// 15
+	// 1
+	// 5
+	// true
+}
+
+func ExampleStream_ForEachControlled() {
+	original := FromSlice([]int{1, 2, 3})
+
+	pause := make(chan bool, 1)
+
+	original.ForEachControlled(func(item int) {
+		fmt.Println(item)
+		if item == 1 {
+			// pause and resume immediately, the channel is polled before the next element.
+			pause <- true
+			go func() { pause <- false }()
+		}
+	}, pause)
+
+	// Output:
+	// 1
+	// 2
+	// 3
+}
+
+func ExampleStream_ForEachReverse() {
+	original := FromSlice([]int{1, 2, 3})
+
+	original.ForEachReverse(func(item int) {
+		fmt.Println(item)
+	})
+
+	// Output:
+	// 3
+	// 2
+	// 1
+}
+
+func ExampleDispatch() {
+	original := FromSlice([]int{0, 1, 2, 3})
+
+	Dispatch(original,
+		Route[int]{
+			Predicate: func(item int) bool { return item == 0 },
+			Action:    func(item int) { fmt.Println("zero:", item) },
+		},
+		Route[int]{
+			Predicate: func(item int) bool { return item%2 == 0 },
+			Action:    func(item int) { fmt.Println("even:", item) },
+		},
+		Route[int]{
+			Action: func(item int) { fmt.Println("odd:", item) },
+		},
+	)
+
+	// Output:
+	// zero: 0
+	// odd: 1
+	// even: 2
+	// odd: 3
+}
+
+func ExampleShardForEach() {
+	original := FromSlice([]int{1, 2, 3, 4, 5})
+
+	shards := make([][]int, 2)
+	ShardForEach(original, 2, func(item int) uint64 { return uint64(item) }, func(shard int, item int) {
+		shards[shard] = append(shards[shard], item)
+	})
+
+	fmt.Println(shards)
+
+	// Output:
+	// [[2 4] [1 3 5]]
+}
+
+func ExampleStream_ParallelForEachOrderedComplete() {
+	original := FromSlice([]int{1, 2, 3, 4})
+
+	original.ParallelForEachOrderedComplete(func(item int) any {
+		return item * item
+	}, func(item int, result any) {
+		fmt.Println(item, result)
+	}, 2)
+
+	// Output:
+	// 1 1
+	// 2 4
+	// 3 9
+	// 4 16
+}
+
+func ExampleStream_ParallelForEachGroup() {
+	original := FromRange(1, 100, 1)
+
+	var sum int64
+	err := original.ParallelForEachGroup(context.Background(), func(ctx context.Context, item int) error {
+		atomic.AddInt64(&sum, int64(item))
+		return nil
+	}, 4)
+
+	fmt.Println(sum)
+	fmt.Println(err)
+
+	// Output:
+	// 5050
+	// <nil>
+}
+
 func ExampleStream_Reduce() {
 	original := FromSlice([]int{1, 2, 3})
 
@@ -303,6 +970,41 @@ func ExampleStream_FindLast() {
 	// true
 }
 
+func ExampleStream_Find() {
+	original := FromSlice([]int{1, 2, 3, 4})
+
+	result, ok := original.Find(func(item int) bool {
+		return item%2 == 0
+	})
+
+	fmt.Println(result)
+	fmt.Println(ok)
+
+	// Output:
+	// 2
+	// true
+}
+
+func ExampleStream_ElementAt() {
+	original := FromSlice([]int{1, 2, 3})
+
+	result, ok := original.ElementAt(1)
+
+	fmt.Println(result)
+	fmt.Println(ok)
+
+	result, ok = original.ElementAt(3)
+
+	fmt.Println(result)
+	fmt.Println(ok)
+
+	// Output:
+	// 2
+	// true
+	// 0
+	// false
+}
+
 func ExampleStream_Reverse() {
 	original := FromSlice([]int{1, 2, 3})
 
@@ -334,6 +1036,47 @@ func ExampleStream_Range() {
 	// [2]
 }
 
+func ExampleWindow() {
+	original := FromSlice([]int{1, 2, 3, 4, 5})
+
+	windows := Window(original, 3, 1)
+
+	fmt.Println(windows.ToSlice())
+
+	// Output:
+	// [[1 2 3] [2 3 4] [3 4 5]]
+}
+
+func ExampleChunkPadded() {
+	original := FromSlice([]int{1, 2, 3, 4, 5, 6, 7})
+
+	chunks := ChunkPadded(original, 3, 0)
+
+	fmt.Println(chunks.ToSlice())
+
+	// Output:
+	// [[1 2 3] [4 5 6] [7 0 0]]
+}
+
+func ExampleSessionWindow() {
+	original := FromSlice([]int{1, 2, 3, 7, 8, 20})
+
+	sums := SessionWindow(original, func(prev, curr int) bool {
+		return curr-prev <= 2
+	}, func(session []int) int {
+		sum := 0
+		for _, v := range session {
+			sum += v
+		}
+		return sum
+	})
+
+	fmt.Println(sums.ToSlice())
+
+	// Output:
+	// [6 15 20]
+}
+
 func ExampleStream_Sorted() {
 	original := FromSlice([]int{4, 2, 1, 3})
 
@@ -347,6 +1090,50 @@ func ExampleStream_Sorted() {
 	// [1 2 3 4]
 }
 
+func ExampleAsc() {
+	original := FromSlice([]int{4, 2, 1, 3})
+
+	sorted := original.Sorted(Asc[int]())
+
+	fmt.Println(sorted.ToSlice())
+
+	// Output:
+	// [1 2 3 4]
+}
+
+func ExampleDesc() {
+	original := FromSlice([]int{4, 2, 1, 3})
+
+	sorted := original.Sorted(Desc[int]())
+
+	fmt.Println(sorted.ToSlice())
+
+	// Output:
+	// [4 3 2 1]
+}
+
+func ExampleByKey() {
+	original := FromSlice([]string{"ccc", "a", "bb"})
+
+	sorted := original.Sorted(ByKey(func(item string) int { return len(item) }))
+
+	fmt.Println(sorted.ToSlice())
+
+	// Output:
+	// [a bb ccc]
+}
+
+func ExampleTopN() {
+	original := FromSlice([]int{5, 1, 9, 3, 7})
+
+	top := TopN(original, 3, func(a, b int) bool { return a < b })
+
+	fmt.Println(top.ToSlice())
+
+	// Output:
+	// [5 7 9]
+}
+
 func ExampleStream_Max() {
 	original := FromSlice([]int{4, 2, 1, 3})
 
@@ -373,6 +1160,122 @@ func ExampleStream_Min() {
 	// true
 }
 
+func ExampleSumMonoid() {
+	m := SumMonoid[float64]()
+
+	fmt.Println(m.Identity)
+	fmt.Println(m.Combine(1.5, 2))
+	fmt.Println(ReduceMonoid(FromSlice([]float64{1.5, 2, 3.5}), m))
+
+	// Output:
+	// 0
+	// 3.5
+	// 7
+}
+
+func ExampleConcatMonoid() {
+	m := ConcatMonoid()
+
+	fmt.Printf("%q\n", m.Identity)
+	fmt.Println(m.Combine("a", "b"))
+	fmt.Println(ReduceMonoid(FromSlice([]string{"x", "y", "z"}), m))
+
+	// Output:
+	// ""
+	// ab
+	// xyz
+}
+
+func ExampleReduceMonoid() {
+	nums := FromSlice([]int{1, 2, 3})
+	strs := FromSlice([]string{"a", "b", "c"})
+
+	fmt.Println(ReduceMonoid(nums, SumMonoid[int]()))
+	fmt.Println(ReduceMonoid(strs, ConcatMonoid()))
+
+	// Output:
+	// 6
+	// abc
+}
+
+func ExampleStream_CountAtMost() {
+	original := FromSlice([]int{1, 2, 3, 4, 5})
+
+	fmt.Println(original.CountAtMost(3))
+	fmt.Println(original.CountAtMost(10))
+
+	// Output:
+	// 3
+	// 5
+}
+
+func ExampleStream_CountBy() {
+	original := FromSlice([]int{1, 2, 3, 4, 5})
+
+	count := original.CountBy(func(item int) bool {
+		return item%2 == 0
+	})
+
+	fmt.Println(count)
+
+	// Output:
+	// 2
+}
+
+func ExampleJoinToString() {
+	original := FromSlice([]int{1, 2, 3})
+
+	result := JoinToString(original, ",", func(item int) string {
+		return strconv.Itoa(item)
+	})
+
+	fmt.Println(result)
+
+	// Output:
+	// 1,2,3
+}
+
+func ExampleJoin() {
+	original := FromSlice([]string{"a", "b", "c"})
+
+	result := Join(original, ",")
+
+	fmt.Println(result)
+
+	// Output:
+	// a,b,c
+}
+
+func ExampleStream_MinMax() {
+	original := FromSlice([]int{4, 2, 1, 3})
+
+	min, max, ok := original.MinMax(func(a, b int) bool { return a < b })
+
+	fmt.Println(min)
+	fmt.Println(max)
+	fmt.Println(ok)
+
+	// Output:
+	// 1
+	// 4
+	// true
+}
+
+func ExampleArgMinMax() {
+	original := FromSlice([]int{4, 1, 9, 3})
+
+	minIdx, maxIdx, ok := ArgMinMax(original)
+
+	fmt.Println(minIdx)
+	fmt.Println(maxIdx)
+	fmt.Println(ok)
+
+	// Output:
+	// 1
+	// 2
+	// true
+}
+
 func ExampleStream_Count() {
 	s1 := FromSlice([]int{1, 2, 3})
 	s2 := FromSlice([]int{})
@@ -384,3 +1287,206 @@ func ExampleStream_Count() {
 	// 3
 	// 0
 }
+
+func ExampleStream_WriteCSV() {
+	original := FromSlice([]int{1, 2, 3})
+
+	err := original.WriteCSV(os.Stdout, func(item int) []string {
+		return []string{strconv.Itoa(item), strconv.Itoa(item * item)}
+	})
+
+	fmt.Println(err)
+
+	// Output:
+	// 1,1
+	// 2,4
+	// 3,9
+	// <nil>
+}
+
+func ExampleStream_Iterator() {
+	original := FromSlice([]int{1, 2, 3})
+
+	next := original.Iterator()
+
+	for item, ok := next(); ok; item, ok = next() {
+		fmt.Println(item)
+	}
+
+	// Output:
+	// 1
+	// 2
+	// 3
+}
+
+func ExampleStream_Backward() {
+	original := FromSlice([]int{1, 2, 3})
+
+	original.Backward()(func(item int) bool {
+		fmt.Println(item)
+		return true
+	})
+
+	// Output:
+	// 3
+	// 2
+	// 1
+}
+
+func ExampleStream_Tee() {
+	original := FromSlice([]int{1, 2, 3})
+
+	s1, s2 := original.Tee()
+
+	fmt.Println(s1.Count())
+	fmt.Println(s2.Reduce(0, func(a, b int) int { return a + b }))
+
+	// Output:
+	// 3
+	// 6
+}
+
+func ExampleStream_ToHeap() {
+	original := FromSlice([]int{3, 1, 2})
+
+	h := original.ToHeap(func(a, b int) bool { return a < b })
+
+	for h.Len() > 0 {
+		v, _ := h.Pop()
+		fmt.Println(v)
+	}
+
+	// Output:
+	// 1
+	// 2
+	// 3
+}
+
+func ExampleToSliceAndIndex() {
+	original := FromSlice([]string{"apple", "banana", "cherry"})
+
+	result, index := ToSliceAndIndex(original, func(item string) byte {
+		return item[0]
+	})
+
+	fmt.Println(result)
+	fmt.Println(result[index['b']])
+
+	// Output:
+	// [apple banana cherry]
+	// banana
+}
+
+func ExampleToSet() {
+	original := FromSlice([]int{1, 2, 2, 3, 3, 3})
+
+	set := ToSet(original)
+
+	fmt.Println(len(set))
+
+	// Output:
+	// 3
+}
+
+func ExampleGroupByCount() {
+	original := FromSlice([]int{1, 2, 3, 4, 5})
+
+	result := GroupByCount(original, func(item int) bool {
+		return item%2 == 0
+	})
+
+	fmt.Println(result)
+
+	// Output:
+	// map[false:3 true:2]
+}
+
+func ExampleSortedCountBy() {
+	original := FromSlice([]int{35, 3, 12, 27, 18, 5, 31})
+
+	result := SortedCountBy(original, func(item int) int {
+		return item / 10 * 10
+	})
+
+	fmt.Println(result)
+
+	// Output:
+	// [{0 2} {10 2} {20 1} {30 2}]
+}
+
+func ExampleToOrderedMap() {
+	original := FromSlice([]string{"c", "a", "b", "a"})
+
+	om := ToOrderedMap(original, func(item string) string { return item }, func(item string) int { return len(item) })
+
+	om.ForEach(func(key string, value int) {
+		fmt.Println(key, value)
+	})
+
+	// Output:
+	// c 1
+	// a 1
+	// b 1
+}
+
+func ExamplePartitionByWeight() {
+	original := FromSlice([]int{1, 9, 2, 8, 3, 7})
+
+	result := PartitionByWeight(original, 2, func(item int) float64 {
+		return float64(item)
+	})
+
+	fmt.Println(result)
+
+	// Output:
+	// [[1 9 2 3] [8 7]]
+}
+
+func ExampleBucketize() {
+	original := FromSlice([]int{7, 2, 9, 4, 1, 10, 3, 8, 5, 6})
+
+	buckets := Bucketize(original, 4)
+
+	fmt.Println(buckets)
+
+	// Output:
+	// [[1 2 3] [4 5 6] [7 8] [9 10]]
+}
+
+func ExampleDiff3() {
+	oldStream := FromSlice([]string{"a", "b", "c"})
+	newStream := FromSlice([]string{"c", "d", "a"})
+
+	added, removed, common := Diff3(oldStream, newStream)
+
+	fmt.Println(added)
+	fmt.Println(removed)
+	fmt.Println(common)
+
+	// Output:
+	// [d]
+	// [b]
+	// [a c]
+}
+
+func ExampleGroupByWindow() {
+	base := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	original := FromSlice([]time.Time{
+		base.Add(10 * time.Minute),
+		base.Add(130 * time.Minute),
+		base.Add(50 * time.Minute),
+		base.Add(70 * time.Minute),
+	})
+
+	windows := GroupByWindow(original, func(item time.Time) time.Time { return item }, time.Hour)
+
+	windows.ForEach(func(item Pair[time.Time, []time.Time]) {
+		fmt.Println(item.Key.Format("15:04"), len(item.Val))
+	})
+
+	// Output:
+	// 00:00 2
+	// 01:00 1
+	// 02:00 1
+}
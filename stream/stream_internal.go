@@ -0,0 +1,34 @@
+package stream
+
+// lessHeap implements heap.Interface, the element at the top is the minimum according to less function.
+type lessHeap[T any] struct {
+	data []T
+	less func(a, b T) bool
+}
+
+func (h *lessHeap[T]) Len() int {
+	return len(h.data)
+}
+
+func (h *lessHeap[T]) Less(i, j int) bool {
+	return h.less(h.data[i], h.data[j])
+}
+
+func (h *lessHeap[T]) Swap(i, j int) {
+	h.data[i], h.data[j] = h.data[j], h.data[i]
+}
+
+func (h *lessHeap[T]) Push(x any) {
+	h.data = append(h.data, x.(T))
+}
+
+func (h *lessHeap[T]) Pop() any {
+	l := len(h.data)
+	item := h.data[l-1]
+
+	var zeroValue T
+	h.data[l-1] = zeroValue
+	h.data = h.data[:l-1]
+
+	return item
+}
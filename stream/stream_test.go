@@ -1,8 +1,18 @@
 package stream
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/duke-git/lancet/v2/internal"
 )
@@ -16,6 +26,50 @@ func TestOf(t *testing.T) {
 	assert.Equal([]int{1, 2, 3}, stream.ToSlice())
 }
 
+func TestOfLimited(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestOfLimited")
+
+	s1 := OfLimited(3, 1, 2, 3, 4, 5)
+	s2 := OfLimited(10, 1, 2, 3)
+	s3 := OfLimited(0, 1, 2, 3)
+	s4 := OfLimited(-1, 1, 2, 3)
+
+	assert.Equal([]int{1, 2, 3}, s1.ToSlice())
+	assert.Equal([]int{1, 2, 3}, s2.ToSlice())
+	assert.Equal([]int{}, s3.ToSlice())
+	assert.Equal([]int{}, s4.ToSlice())
+}
+
+func TestFromNonZero(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestFromNonZero")
+
+	s1 := FromNonZero(1, 0, 2, 0, 3)
+	s2 := FromNonZero("a", "", "b")
+	s3 := FromNonZero(0, 0)
+
+	assert.Equal([]int{1, 2, 3}, s1.ToSlice())
+	assert.Equal([]string{"a", "b"}, s2.ToSlice())
+	assert.Equal([]int{}, s3.ToSlice())
+}
+
+func TestOfSlices(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestOfSlices")
+
+	s1 := OfSlices([]int{1, 2}, []int{}, []int{3, 4, 5})
+	s2 := OfSlices([]int{1}, nil, []int{2})
+	s3 := OfSlices[int]()
+
+	assert.Equal([]int{1, 2, 3, 4, 5}, s1.ToSlice())
+	assert.Equal([]int{1, 2}, s2.ToSlice())
+	assert.Equal([]int{}, s3.ToSlice())
+}
+
 func TestGenerate(t *testing.T) {
 	t.Parallel()
 
@@ -45,6 +99,41 @@ func TestFromSlice(t *testing.T) {
 	assert.Equal([]int{1, 2, 3}, stream.ToSlice())
 }
 
+func TestFromSliceOwned(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestFromSliceOwned")
+
+	source := []int{3, 1, 2}
+	stream := FromSliceOwned(source)
+
+	data := stream.ToSlice()
+	data[0] = 100
+
+	assert.Equal([]int{100, 1, 2}, source)
+	assert.Equal([]int{100, 1, 2}, stream.ToSlice())
+
+	// FromSlice has the same ownership semantics.
+	FromSlice(source).ToSlice()[1] = 200
+	assert.Equal([]int{100, 200, 2}, source)
+}
+
+func TestFromSliceCopy(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestFromSliceCopy")
+
+	source := []int{3, 1, 2}
+	stream := FromSliceCopy(source)
+
+	data := stream.ToSlice()
+	data[0] = 100
+	source[1] = 200
+
+	assert.Equal([]int{3, 200, 2}, source)
+	assert.Equal([]int{100, 1, 2}, stream.ToSlice())
+}
+
 func TestFromChannel(t *testing.T) {
 	t.Parallel()
 
@@ -63,6 +152,80 @@ func TestFromChannel(t *testing.T) {
 	assert.Equal([]int{1, 2, 3}, stream.ToSlice())
 }
 
+func TestFromMap(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestFromMap")
+
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+
+	stream := FromMap(m)
+
+	assert.Equal(len(m), stream.Count())
+
+	stream.ForEach(func(item Pair[string, int]) {
+		assert.Equal(m[item.Key], item.Val)
+	})
+
+	assert.Equal(0, FromMap(map[string]int{}).Count())
+}
+
+func TestFromMapSorted(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestFromMapSorted")
+
+	m := map[string]int{"c": 3, "a": 1, "b": 2}
+
+	stream := FromMapSorted(m, func(a, b string) bool { return a < b })
+
+	assert.Equal(len(m), stream.Count())
+	assert.Equal([]Pair[string, int]{
+		{Key: "a", Val: 1},
+		{Key: "b", Val: 2},
+		{Key: "c", Val: 3},
+	}, stream.ToSlice())
+}
+
+func TestFromCSV(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestFromCSV")
+
+	type Person struct {
+		Name string
+		Age  int
+	}
+
+	mapper := func(record []string) (Person, error) {
+		if len(record) != 2 {
+			return Person{}, errors.New("invalid record")
+		}
+		age, err := strconv.Atoi(record[1])
+		if err != nil {
+			return Person{}, err
+		}
+		return Person{Name: record[0], Age: age}, nil
+	}
+
+	data := "Tom,10\nJim,20\nMike,30\n"
+
+	stream, err := FromCSV(strings.NewReader(data), mapper)
+
+	assert.IsNil(err)
+	assert.Equal([]Person{
+		{Name: "Tom", Age: 10},
+		{Name: "Jim", Age: 20},
+		{Name: "Mike", Age: 30},
+	}, stream.ToSlice())
+
+	_, err = FromCSV(strings.NewReader("Tom,10\nJim,abc\n"), mapper)
+	assert.IsNotNil(err)
+
+	_, err = FromCSV(strings.NewReader("Tom,10\nJim\n"), mapper)
+	assert.IsNotNil(err)
+}
+
 func TestFromRange(t *testing.T) {
 	t.Parallel()
 
@@ -75,6 +238,161 @@ func TestFromRange(t *testing.T) {
 	assert.Equal([]float64{1.1, 2.1, 3.1, 4.1}, s2.ToSlice())
 }
 
+func TestFromRangeN(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestFromRangeN")
+
+	s1 := FromRangeN(0.0, 0.1, 11)
+	s2 := FromRangeN(1, 2, 4)
+	s3 := FromRangeN(5, -1, 3)
+	s4 := FromRangeN(1, 1, 0)
+
+	first, _ := s1.FindFirst()
+	last, _ := s1.FindLast()
+
+	assert.Equal(11, s1.Count())
+	assert.Equal(0.0, first)
+	assert.Equal(true, math.Abs(last-1.0) < 1e-9)
+	assert.Equal([]int{1, 3, 5, 7}, s2.ToSlice())
+	assert.Equal([]int{5, 4, 3}, s3.ToSlice())
+	assert.Equal([]int{}, s4.ToSlice())
+
+	defer func() {
+		r := recover()
+		assert.IsNotNil(r)
+	}()
+
+	FromRangeN(1, 1, -1)
+}
+
+func TestFromRange_Overflow(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestFromRange_Overflow")
+
+	s1 := FromRange[int32](math.MaxInt32-2, math.MaxInt32, 1)
+	assert.Equal([]int32{math.MaxInt32 - 2, math.MaxInt32 - 1, math.MaxInt32}, s1.ToSlice())
+
+	s2 := FromRange[int32](math.MinInt32, math.MaxInt32, math.MaxInt32)
+	assert.Equal([]int32{math.MinInt32, -1, math.MaxInt32 - 1}, s2.ToSlice())
+
+	s3 := FromRange[int64](math.MaxInt64-4, math.MaxInt64, 2)
+	assert.Equal([]int64{math.MaxInt64 - 4, math.MaxInt64 - 2, math.MaxInt64}, s3.ToSlice())
+
+	s4 := FromRange[int64](math.MinInt64, math.MaxInt64, math.MaxInt64)
+	assert.Equal([]int64{math.MinInt64, -1, math.MaxInt64 - 1}, s4.ToSlice())
+
+	s5 := FromRange[int8](-128, 127, 100)
+	assert.Equal([]int8{-128, -28, 72}, s5.ToSlice())
+
+	s6 := FromRange[uint64](math.MaxUint64-1, math.MaxUint64, 1)
+	assert.Equal([]uint64{math.MaxUint64 - 1, math.MaxUint64}, s6.ToSlice())
+
+	defer func() {
+		r := recover()
+		assert.IsNotNil(r)
+	}()
+
+	FromRange[int64](math.MinInt64, math.MaxInt64, 1)
+}
+
+func TestFromRange_FloatRounding(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestFromRange_FloatRounding")
+
+	s1 := FromRange(0.0, 1.7, 0.1)
+	last, _ := s1.FindLast()
+
+	assert.Equal(18, s1.Count())
+	assert.Equal(1.7, last)
+	assert.Equal(true, s1.AllMatch(func(item float64) bool { return item <= 1.7 }))
+
+	s2 := FromRange(0.0, 1.0, 0.25)
+	assert.Equal([]float64{0, 0.25, 0.5, 0.75, 1.0}, s2.ToSlice())
+
+	s3 := FromRange(0.0, 0.3, 0.1)
+	assert.Equal([]float64{0, 0.1, 0.2, 0.3}, s3.ToSlice())
+
+	s4 := FromRange(0.0, 0.7, 0.1)
+	last, _ = s4.FindLast()
+	assert.Equal(8, s4.Count())
+	assert.Equal(0.7, last)
+
+	s5 := FromRange(1e6, 1e6+0.3, 0.1)
+	last, _ = s5.FindLast()
+	assert.Equal(4, s5.Count())
+	assert.Equal(1e6+0.3, last)
+
+	s6 := FromRange[float32](0, 0.3, 0.1)
+	last32, _ := s6.FindLast()
+	assert.Equal(4, s6.Count())
+	assert.Equal(float32(0.3), last32)
+
+	s7 := FromRange(0.0, 0.35, 0.1)
+	last, _ = s7.FindLast()
+	assert.Equal(4, s7.Count())
+	assert.Equal(true, last < 0.35)
+
+	defer func() {
+		r := recover()
+		assert.IsNotNil(r)
+	}()
+
+	FromRange(-math.MaxFloat64, math.MaxFloat64, 1)
+}
+
+func TestEmpty(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestEmpty")
+
+	s := Empty[int]()
+
+	assert.Equal(0, s.Count())
+	assert.Equal([]int{}, s.ToSlice())
+}
+
+func TestRepeat(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestRepeat")
+
+	s1 := Repeat("a", 3)
+	s2 := Repeat(1, 0)
+
+	assert.Equal([]string{"a", "a", "a"}, s1.ToSlice())
+	assert.Equal(0, s2.Count())
+
+	defer func() {
+		r := recover()
+		assert.IsNotNil(r)
+	}()
+
+	Repeat(1, -1)
+}
+
+func TestCycle(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestCycle")
+
+	assert.Equal([]int{1, 2, 1, 2, 1, 2}, Cycle([]int{1, 2}, 3).ToSlice())
+	assert.Equal([]int{7, 7, 7}, Cycle([]int{7}, 3).ToSlice())
+	assert.Equal([]int{1, 2}, Cycle([]int{1, 2}, 1).ToSlice())
+	assert.Equal([]int{}, Cycle([]int{1, 2}, 0).ToSlice())
+	assert.Equal([]int{}, Cycle([]int{}, 3).ToSlice())
+	assert.Equal([]int{}, Cycle[int](nil, 3).ToSlice())
+
+	defer func() {
+		r := recover()
+		assert.IsNotNil(r)
+	}()
+
+	Cycle([]int{1, 2}, -1)
+}
+
 func TestStream_Distinct(t *testing.T) {
 	t.Parallel()
 
@@ -109,275 +427,2051 @@ func TestStream_Distinct(t *testing.T) {
 	t.Log(distinctStream)
 }
 
-func TestStream_Filter(t *testing.T) {
+func TestDistinctSortedAssumeSorted(t *testing.T) {
 	t.Parallel()
 
-	assert := internal.NewAssert(t, "TestStream_Filter")
-
-	stream := FromSlice([]int{1, 2, 3, 4, 5})
+	assert := internal.NewAssert(t, "TestDistinctSortedAssumeSorted")
 
-	isEven := func(n int) bool {
-		return n%2 == 0
-	}
+	nums := FromSlice([]int{1, 1, 2, 3, 3, 3, 4, 5, 5})
+	distinctNums := DistinctSortedAssumeSorted(nums)
 
-	even := stream.Filter(isEven)
+	assert.Equal([]int{1, 1, 2, 3, 3, 3, 4, 5, 5}, nums.ToSlice())
+	assert.Equal([]int{1, 2, 3, 4, 5}, distinctNums.ToSlice())
 
-	assert.Equal([]int{2, 4}, even.ToSlice())
+	empty := DistinctSortedAssumeSorted(FromSlice([]int{}))
+	assert.Equal([]int{}, empty.ToSlice())
 }
 
-func TestStream_Map(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_Map")
+func TestDistinctByWithKeys(t *testing.T) {
+	t.Parallel()
 
-	stream := FromSlice([]int{1, 2, 3})
+	assert := internal.NewAssert(t, "TestDistinctByWithKeys")
 
-	addOne := func(n int) int {
-		return n + 1
+	type Record struct {
+		Id   int
+		Name string
 	}
 
-	s := stream.Map(addOne)
+	records := FromSlice([]Record{
+		{Id: 2, Name: "b"},
+		{Id: 1, Name: "a"},
+		{Id: 2, Name: "bb"},
+		{Id: 3, Name: "c"},
+		{Id: 1, Name: "aa"},
+	})
 
-	assert.Equal([]int{2, 3, 4}, s.ToSlice())
-}
+	distinct, ids := DistinctByWithKeys(records, func(r Record) int { return r.Id })
 
-func TestStream_Peek(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_Peek")
+	assert.Equal([]Record{
+		{Id: 2, Name: "b"},
+		{Id: 1, Name: "a"},
+		{Id: 3, Name: "c"},
+	}, distinct.ToSlice())
+	assert.Equal([]int{2, 1, 3}, ids)
+}
 
-	stream := FromSlice([]int{1, 2, 3, 4, 5, 6})
+func TestDistinctByKeepLatest(t *testing.T) {
+	t.Parallel()
 
-	result := []string{}
-	stream = stream.Filter(func(n int) bool {
-		return n <= 3
-	}).Peek(func(n int) {
-		result = append(result, fmt.Sprint("current: ", n))
-	})
+	assert := internal.NewAssert(t, "TestDistinctByKeepLatest")
 
-	assert.Equal([]int{1, 2, 3}, stream.ToSlice())
-	assert.Equal([]string{
-		"current: 1", "current: 2", "current: 3",
-	}, result)
-}
+	type Event struct {
+		Key   string
+		Value int
+	}
 
-func TestStream_Skip(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_Peek")
+	events := FromSlice([]Event{
+		{Key: "a", Value: 1},
+		{Key: "b", Value: 2},
+		{Key: "a", Value: 3},
+		{Key: "c", Value: 4},
+		{Key: "b", Value: 5},
+	})
 
-	stream := FromSlice([]int{1, 2, 3, 4})
+	latest := DistinctByKeepLatest(events, func(e Event) string { return e.Key })
 
-	s1 := stream.Skip(-1)
-	s2 := stream.Skip(0)
-	s3 := stream.Skip(1)
-	s4 := stream.Skip(2)
+	assert.Equal([]Event{
+		{Key: "a", Value: 3},
+		{Key: "c", Value: 4},
+		{Key: "b", Value: 5},
+	}, latest.ToSlice())
 
-	assert.Equal([]int{1, 2, 3, 4}, s1.ToSlice())
-	assert.Equal([]int{1, 2, 3, 4}, s2.ToSlice())
-	assert.Equal([]int{2, 3, 4}, s3.ToSlice())
-	assert.Equal([]int{3, 4}, s4.ToSlice())
+	empty := DistinctByKeepLatest(FromSlice([]Event{}), func(e Event) string { return e.Key })
+	assert.Equal([]Event{}, empty.ToSlice())
 }
 
-func TestStream_Limit(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_Limit")
+func TestDedupConsecutive(t *testing.T) {
+	t.Parallel()
 
-	stream := FromSlice([]int{1, 2, 3, 4, 5, 6})
+	assert := internal.NewAssert(t, "TestDedupConsecutive")
 
-	s1 := stream.Limit(-1)
-	s2 := stream.Limit(0)
-	s3 := stream.Limit(1)
-	s4 := stream.Limit(6)
+	nums := FromSlice([]int{1, 1, 2, 2, 1})
 
-	assert.Equal([]int{}, s1.ToSlice())
-	assert.Equal([]int{}, s2.ToSlice())
-	assert.Equal([]int{1}, s3.ToSlice())
-	assert.Equal([]int{1, 2, 3, 4, 5, 6}, s4.ToSlice())
+	assert.Equal([]int{1, 2, 1}, DedupConsecutive(nums).ToSlice())
+	assert.Equal([]int{1, 2}, nums.Distinct().ToSlice())
+
+	assert.Equal([]int{}, DedupConsecutive(FromSlice([]int{})).ToSlice())
+	assert.Equal([]int{1}, DedupConsecutive(FromSlice([]int{1})).ToSlice())
+	assert.Equal([]string{"a"}, DedupConsecutive(FromSlice([]string{"a", "a", "a"})).ToSlice())
 }
 
-func TestStream_AllMatch(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_AllMatch")
+func TestStream_DistinctDeep(t *testing.T) {
+	t.Parallel()
 
-	stream := FromSlice([]int{1, 2, 3, 4, 5, 6})
+	assert := internal.NewAssert(t, "TestStream_DistinctDeep")
 
-	result1 := stream.AllMatch(func(item int) bool {
-		return item > 0
-	})
+	nums := FromSlice([]int{1, 2, 2, 3, 3, 3})
+	assert.Equal([]int{1, 2, 3}, nums.DistinctDeep().ToSlice())
 
-	result2 := stream.AllMatch(func(item int) bool {
-		return item > 1
+	type Task struct {
+		Name string
+		Tags []string
+		Run  func()
+	}
+
+	tasks := FromSlice([]Task{
+		{Name: "a", Tags: []string{"x"}},
+		{Name: "b", Tags: []string{"y"}},
+		{Name: "a", Tags: []string{"x"}},
+		{Name: "a", Tags: []string{"z"}},
 	})
 
-	assert.Equal(true, result1)
-	assert.Equal(false, result2)
-}
+	distinct := tasks.DistinctDeep()
 
-func TestStream_AnyMatch(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_AnyMatch")
+	assert.Equal([]Task{
+		{Name: "a", Tags: []string{"x"}},
+		{Name: "b", Tags: []string{"y"}},
+		{Name: "a", Tags: []string{"z"}},
+	}, distinct.ToSlice())
 
-	stream := FromSlice([]int{1, 2, 3})
+	run := func() {}
+	withFunc := FromSlice([]Task{{Name: "a", Run: run}, {Name: "a", Run: run}})
+	assert.Equal(2, withFunc.DistinctDeep().Count())
 
-	result1 := stream.AnyMatch(func(item int) bool {
-		return item > 3
-	})
+	// gob can't encode the struct which has no exported fields, so Distinct panics.
+	type point struct {
+		x, y int
+	}
 
-	result2 := stream.AnyMatch(func(item int) bool {
-		return item > 1
-	})
+	points := FromSlice([]point{{1, 2}, {3, 4}, {1, 2}})
+	assert.Equal([]point{{1, 2}, {3, 4}}, points.DistinctDeep().ToSlice())
 
-	assert.Equal(false, result1)
-	assert.Equal(true, result2)
+	defer func() {
+		r := recover()
+		assert.IsNotNil(r)
+	}()
+
+	points.Distinct()
 }
 
-func TestStream_NoneMatch(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_NoneMatch")
+func TestStream_Filter(t *testing.T) {
+	t.Parallel()
 
-	stream := FromSlice([]int{1, 2, 3})
+	assert := internal.NewAssert(t, "TestStream_Filter")
 
-	result1 := stream.NoneMatch(func(item int) bool {
-		return item > 3
-	})
+	stream := FromSlice([]int{1, 2, 3, 4, 5})
 
-	result2 := stream.NoneMatch(func(item int) bool {
-		return item > 1
-	})
+	isEven := func(n int) bool {
+		return n%2 == 0
+	}
 
-	assert.Equal(true, result1)
-	assert.Equal(false, result2)
+	even := stream.Filter(isEven)
+
+	assert.Equal([]int{2, 4}, even.ToSlice())
 }
 
-func TestStream_ForEach(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_ForEach")
+func TestFilterDistinct(t *testing.T) {
+	t.Parallel()
 
-	stream := FromSlice([]int{1, 2, 3})
+	assert := internal.NewAssert(t, "TestFilterDistinct")
 
-	result := 0
-	stream.ForEach(func(item int) {
-		result += item
-	})
+	stream := FromSlice([]int{4, 1, 2, 4, 3, 2, 6, 4})
+
+	isEven := func(n int) bool {
+		return n%2 == 0
+	}
+
+	assert.Equal([]int{4, 2, 6}, FilterDistinct(stream, isEven).ToSlice())
+	assert.Equal([]int{}, FilterDistinct(FromSlice([]int{1, 3}), isEven).ToSlice())
+}
+
+func TestStream_FilterContext(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_FilterContext")
+
+	stream := FromSlice([]int{1, 2, 3, 4, 5, 6})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	even, err := stream.FilterContext(ctx, func(n int) bool {
+		if n == 4 {
+			cancel()
+		}
+		return n%2 == 0
+	})
+
+	assert.Equal(context.Canceled, err)
+	assert.Equal([]int{2, 4}, even.ToSlice())
+
+	even, err = stream.FilterContext(context.Background(), func(n int) bool {
+		return n%2 == 0
+	})
+
+	assert.IsNil(err)
+	assert.Equal([]int{2, 4, 6}, even.ToSlice())
+}
+
+func TestStream_Map(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_Map")
+
+	stream := FromSlice([]int{1, 2, 3})
+
+	addOne := func(n int) int {
+		return n + 1
+	}
+
+	s := stream.Map(addOne)
+
+	assert.Equal([]int{2, 3, 4}, s.ToSlice())
+}
+
+func TestFilterMap(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestFilterMap")
+
+	stream := FromSlice([]string{"1", "a", "2", "", "3", "b"})
+
+	nums := FilterMap(stream, func(item string) (int, bool) {
+		n, err := strconv.Atoi(item)
+		return n, err == nil
+	})
+
+	assert.Equal(3, nums.Count())
+	assert.Equal([]int{1, 2, 3}, nums.ToSlice())
+
+	empty := FilterMap(stream, func(item string) (int, bool) {
+		return 0, false
+	})
+
+	assert.Equal([]int{}, empty.ToSlice())
+}
+
+func TestMapUntilError(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestMapUntilError")
+
+	stream := FromSlice([]string{"1", "2", "a", "4"})
+
+	nums, err := MapUntilError(stream, strconv.Atoi)
+
+	assert.IsNotNil(err)
+	assert.Equal([]int{1, 2}, nums.ToSlice())
+
+	nums, err = MapUntilError(FromSlice([]string{"1", "2", "3"}), strconv.Atoi)
+
+	assert.IsNil(err)
+	assert.Equal([]int{1, 2, 3}, nums.ToSlice())
+}
+
+func TestMapErr(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestMapErr")
+
+	errFirst := errors.New("first error")
+	errSecond := errors.New("second error")
+
+	calls := 0
+	mapper := func(item string) (int, error) {
+		calls++
+		switch item {
+		case "a":
+			return 0, errFirst
+		case "b":
+			return 0, errSecond
+		}
+		return strconv.Atoi(item)
+	}
+
+	nums, err := MapErr(FromSlice([]string{"1", "2", "a", "b", "5"}), mapper)
+
+	assert.Equal(true, errors.Is(err, errFirst))
+	assert.Equal("stream.MapErr: element at index 2: first error", err.Error())
+	assert.Equal(3, calls)
+	assert.Equal(0, nums.Count())
+
+	nums, err = MapErr(FromSlice([]string{"1", "2", "3"}), mapper)
+
+	assert.IsNil(err)
+	assert.Equal([]int{1, 2, 3}, nums.ToSlice())
+}
+
+func TestMapMemoized(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestMapMemoized")
+
+	stream := FromSlice([]int{1, 2, 1, 3, 2, 1})
+
+	calls := map[int]int{}
+	square := func(n int) int {
+		calls[n]++
+		return n * n
+	}
+
+	result := MapMemoized(stream, square)
+
+	assert.Equal([]int{1, 4, 1, 9, 4, 1}, result.ToSlice())
+	assert.Equal(map[int]int{1: 1, 2: 1, 3: 1}, calls)
+}
+
+func TestFlatMapSlice(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestFlatMapSlice")
+
+	type Order struct {
+		Id    int
+		Items []string
+	}
+
+	orders := FromSlice([]Order{
+		{Id: 1, Items: []string{"a", "b"}},
+		{Id: 2, Items: nil},
+		{Id: 3, Items: []string{}},
+		{Id: 4, Items: []string{"c", "d", "e"}},
+	})
+
+	items := FlatMapSlice(orders, func(o Order) []string {
+		return o.Items
+	})
+
+	assert.Equal(5, items.Count())
+	assert.Equal([]string{"a", "b", "c", "d", "e"}, items.ToSlice())
+}
+
+func TestMapEachMapValue(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestMapEachMapValue")
+
+	stream := FromSlice([]map[string]int{
+		{"a": 1, "b": 2},
+		{},
+		{"c": 3},
+	})
+
+	double := MapEachMapValue(stream, func(v int) int {
+		return v * 2
+	})
+
+	assert.Equal([]map[string]int{
+		{"a": 2, "b": 4},
+		{},
+		{"c": 6},
+	}, double.ToSlice())
+
+	assert.Equal(map[string]int{"a": 1, "b": 2}, stream.ToSlice()[0])
+}
+
+func TestFlatten(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestFlatten")
+
+	stream := FromSlice([][]int{{1, 2}, nil, {}, {3}, {4, 5, 6}})
+
+	result := Flatten(stream)
+
+	assert.Equal(6, result.Count())
+	assert.Equal([]int{1, 2, 3, 4, 5, 6}, result.ToSlice())
+
+	assert.Equal([]int{}, Flatten(FromSlice([][]int{nil, {}})).ToSlice())
+}
+
+func TestStream_MapPipeline(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_MapPipeline")
+
+	stream := FromSlice([]int{1, 2, 3})
+
+	increment := func(n int) int { return n + 1 }
+	double := func(n int) int { return n * 2 }
+
+	assert.Equal([]int{4, 6, 8}, stream.MapPipeline(increment, double).ToSlice())
+	assert.Equal([]int{3, 5, 7}, stream.MapPipeline(double, increment).ToSlice())
+	assert.Equal([]int{1, 2, 3}, stream.MapPipeline().ToSlice())
+}
+
+func BenchmarkStream_MapPipeline(b *testing.B) {
+	s := FromRange(0, 9999, 1)
+
+	increment := func(n int) int { return n + 1 }
+	double := func(n int) int { return n * 2 }
+
+	b.Run("MapPipeline", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			result := s.MapPipeline(increment, double)
+			if result.Count() != s.Count() {
+				b.Fatal("unexpected result count")
+			}
+		}
+	})
+
+	b.Run("ChainedMap", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			result := s.Map(increment).Map(double)
+			if result.Count() != s.Count() {
+				b.Fatal("unexpected result count")
+			}
+		}
+	})
+}
+
+func TestParallelMapBounded(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestParallelMapBounded")
+
+	stream := FromRange(0, 49, 1)
+
+	result := ParallelMapBounded(stream, func(item int) string {
+		time.Sleep(time.Duration(item%3) * time.Millisecond)
+		return strconv.Itoa(item)
+	}, 8)
+
+	expected := FilterMap(stream, func(item int) (string, bool) {
+		return strconv.Itoa(item), true
+	})
+	assert.Equal(expected.ToSlice(), result.ToSlice())
+
+	assert.Equal([]int{}, ParallelMapBounded(FromSlice([]int{}), func(item int) int { return item }, 2).ToSlice())
+}
+
+func TestParallelMapBounded_InFlight(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestParallelMapBounded_InFlight")
+
+	const maxInFlight = 3
+
+	var inFlight, maxSeen int64
+
+	ParallelMapBounded(FromRange(0, 29, 1), func(item int) int {
+		current := atomic.AddInt64(&inFlight, 1)
+		for {
+			seen := atomic.LoadInt64(&maxSeen)
+			if current <= seen || atomic.CompareAndSwapInt64(&maxSeen, seen, current) {
+				break
+			}
+		}
+
+		time.Sleep(2 * time.Millisecond)
+		atomic.AddInt64(&inFlight, -1)
+
+		return item
+	}, maxInFlight)
+
+	assert.Equal(true, atomic.LoadInt64(&maxSeen) <= maxInFlight)
+	assert.Equal(true, atomic.LoadInt64(&maxSeen) > 1)
+}
+
+func TestStream_Peek(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_Peek")
+
+	stream := FromSlice([]int{1, 2, 3, 4, 5, 6})
+
+	result := []string{}
+	stream = stream.Filter(func(n int) bool {
+		return n <= 3
+	}).Peek(func(n int) {
+		result = append(result, fmt.Sprint("current: ", n))
+	})
+
+	assert.Equal([]int{1, 2, 3}, stream.ToSlice())
+	assert.Equal([]string{
+		"current: 1", "current: 2", "current: 3",
+	}, result)
+}
+
+func TestStream_PeekOrder(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_PeekOrder")
+
+	stream := FromSlice([]int{1, 2, 3, 4})
+
+	events := []string{}
+	peeked := stream.Peek(func(n int) {
+		events = append(events, fmt.Sprint("peek: ", n))
+	})
+
+	even := peeked.Filter(func(n int) bool {
+		events = append(events, fmt.Sprint("filter: ", n))
+		return n%2 == 0
+	})
+
+	assert.Equal([]int{2, 4}, even.ToSlice())
+	assert.Equal([]string{
+		"peek: 1", "peek: 2", "peek: 3", "peek: 4",
+		"filter: 1", "filter: 2", "filter: 3", "filter: 4",
+	}, events)
+
+	peeked.ToSlice()[0] = 100
+
+	assert.Equal([]int{1, 2, 3, 4}, stream.ToSlice())
+}
+
+func TestStream_Skip(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_Peek")
+
+	stream := FromSlice([]int{1, 2, 3, 4})
+
+	s1 := stream.Skip(-1)
+	s2 := stream.Skip(0)
+	s3 := stream.Skip(1)
+	s4 := stream.Skip(2)
+	s5 := stream.Skip(4)
+	s6 := stream.Skip(5)
+
+	assert.Equal([]int{1, 2, 3, 4}, s1.ToSlice())
+	assert.Equal([]int{1, 2, 3, 4}, s2.ToSlice())
+	assert.Equal([]int{2, 3, 4}, s3.ToSlice())
+	assert.Equal([]int{3, 4}, s4.ToSlice())
+	assert.Equal([]int{}, s5.ToSlice())
+	assert.Equal([]int{}, s6.ToSlice())
+
+	s3.ToSlice()[0] = 100
+	assert.Equal([]int{1, 2, 3, 4}, stream.ToSlice())
+}
+
+func TestStream_SkipWhile(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_SkipWhile")
+
+	stream := FromSlice([]int{1, 2, 3, 4, 1})
+
+	s1 := stream.SkipWhile(func(n int) bool { return n < 3 })
+	s2 := stream.SkipWhile(func(n int) bool { return n > 10 })
+	s3 := stream.SkipWhile(func(n int) bool { return n < 10 })
+
+	assert.Equal([]int{3, 4, 1}, s1.ToSlice())
+	assert.Equal([]int{1, 2, 3, 4, 1}, s2.ToSlice())
+	assert.Equal([]int{}, s3.ToSlice())
+}
+
+func TestStream_Trim(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_Trim")
+
+	isZero := func(n int) bool {
+		return n == 0
+	}
+
+	assert.Equal([]int{1, 0, 2}, FromSlice([]int{0, 0, 1, 0, 2, 0, 0}).Trim(isZero).ToSlice())
+	assert.Equal([]int{1, 2}, FromSlice([]int{1, 2}).Trim(isZero).ToSlice())
+	assert.Equal([]int{}, FromSlice([]int{0, 0, 0}).Trim(isZero).ToSlice())
+	assert.Equal([]int{}, FromSlice([]int{}).Trim(isZero).ToSlice())
+}
+
+func TestStream_Limit(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_Limit")
+
+	stream := FromSlice([]int{1, 2, 3, 4, 5, 6})
+
+	s1 := stream.Limit(-1)
+	s2 := stream.Limit(0)
+	s3 := stream.Limit(1)
+	s4 := stream.Limit(6)
+
+	assert.Equal([]int{}, s1.ToSlice())
+	assert.Equal([]int{}, s2.ToSlice())
+	assert.Equal([]int{1}, s3.ToSlice())
+	assert.Equal([]int{1, 2, 3, 4, 5, 6}, s4.ToSlice())
+}
+
+func TestStream_LimitWhile(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_LimitWhile")
+
+	stream := FromSlice([]int{1, 2, 3, 4, 1})
+
+	s1 := stream.LimitWhile(func(n int) bool { return n < 3 })
+	s2 := stream.LimitWhile(func(n int) bool { return n > 10 })
+	s3 := stream.LimitWhile(func(n int) bool { return n < 10 })
+
+	assert.Equal([]int{1, 2}, s1.ToSlice())
+	assert.Equal([]int{}, s2.ToSlice())
+	assert.Equal([]int{1, 2, 3, 4, 1}, s3.ToSlice())
+}
+
+func TestStream_LimitSigned(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_LimitSigned")
+
+	stream := FromSlice([]int{1, 2, 3, 4, 5, 6})
+
+	assert.Equal([]int{}, stream.LimitSigned(0).ToSlice())
+	assert.Equal([]int{1, 2}, stream.LimitSigned(2).ToSlice())
+	assert.Equal([]int{1, 2, 3, 4, 5, 6}, stream.LimitSigned(10).ToSlice())
+	assert.Equal([]int{1, 2, 3, 4, 5}, stream.LimitSigned(-1).ToSlice())
+	assert.Equal([]int{1, 2}, stream.LimitSigned(-4).ToSlice())
+	assert.Equal([]int{}, stream.LimitSigned(-6).ToSlice())
+	assert.Equal([]int{}, stream.LimitSigned(-10).ToSlice())
+}
+
+func TestStream_TakeEvery(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_TakeEvery")
+
+	stream := FromRange(0, 9, 1)
+
+	assert.Equal([]int{1, 4, 7}, stream.TakeEvery(3, 1).ToSlice())
+	assert.Equal([]int{0, 2, 4, 6, 8}, stream.TakeEvery(2, 0).ToSlice())
+	assert.Equal([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, stream.TakeEvery(1, 0).ToSlice())
+	assert.Equal([]int{}, stream.TakeEvery(1, 10).ToSlice())
+
+	defer func() {
+		r := recover()
+		assert.IsNotNil(r)
+	}()
+
+	stream.TakeEvery(0, 0)
+}
+
+func TestStream_SampleIndices(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_SampleIndices")
+
+	stream := FromRange(0, 19, 1)
+
+	indices := stream.SampleIndices(5, rand.New(rand.NewSource(42)))
+
+	assert.Equal(5, len(indices))
+	assert.Equal(5, len(ToSet(FromSlice(indices))))
+	for i, index := range indices {
+		assert.Equal(true, index >= 0 && index < stream.Count())
+		if i > 0 {
+			assert.Equal(true, indices[i-1] < index)
+		}
+	}
+
+	assert.Equal(indices, stream.SampleIndices(5, rand.New(rand.NewSource(42))))
+	assert.Equal(stream.ToSlice(), stream.SampleIndices(20, rand.New(rand.NewSource(42))))
+	assert.Equal([]int{}, stream.SampleIndices(0, rand.New(rand.NewSource(42))))
+
+	defer func() {
+		r := recover()
+		assert.IsNotNil(r)
+	}()
+
+	stream.SampleIndices(21, rand.New(rand.NewSource(42)))
+}
+
+func TestStream_AllMatch(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_AllMatch")
+
+	stream := FromSlice([]int{1, 2, 3, 4, 5, 6})
+
+	result1 := stream.AllMatch(func(item int) bool {
+		return item > 0
+	})
+
+	result2 := stream.AllMatch(func(item int) bool {
+		return item > 1
+	})
+
+	assert.Equal(true, result1)
+	assert.Equal(false, result2)
+}
+
+func TestStream_AnyMatch(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_AnyMatch")
+
+	stream := FromSlice([]int{1, 2, 3})
+
+	result1 := stream.AnyMatch(func(item int) bool {
+		return item > 3
+	})
+
+	result2 := stream.AnyMatch(func(item int) bool {
+		return item > 1
+	})
+
+	assert.Equal(false, result1)
+	assert.Equal(true, result2)
+}
+
+func TestStream_NoneMatch(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_NoneMatch")
+
+	stream := FromSlice([]int{1, 2, 3})
+
+	result1 := stream.NoneMatch(func(item int) bool {
+		return item > 3
+	})
+
+	result2 := stream.NoneMatch(func(item int) bool {
+		return item > 1
+	})
+
+	assert.Equal(true, result1)
+	assert.Equal(false, result2)
+}
+
+func TestContains(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestContains")
+
+	stream := FromSlice([]int{1, 2, 3})
+
+	assert.Equal(true, Contains(stream, 1))
+	assert.Equal(true, Contains(stream, 3))
+	assert.Equal(false, Contains(stream, 4))
+	assert.Equal(false, Contains(FromSlice([]int{}), 1))
+}
+
+func TestStream_ForEach(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_ForEach")
+
+	stream := FromSlice([]int{1, 2, 3})
+
+	result := 0
+	stream.ForEach(func(item int) {
+		result += item
+	})
+
+	assert.Equal(6, result)
+}
+
+func TestStream_ForEachErr(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_ForEachErr")
+
+	stream := FromSlice([]int{1, 2, 3, 4, 5})
+
+	errFirst := errors.New("first error")
+	errSecond := errors.New("second error")
+
+	result := []int{}
+	err := stream.ForEachErr(func(item int) error {
+		result = append(result, item)
+		if item == 3 {
+			return errFirst
+		}
+		if item == 4 {
+			return errSecond
+		}
+		return nil
+	})
+
+	assert.Equal(true, errors.Is(err, errFirst))
+	assert.Equal("stream.ForEachErr: element at index 2: first error", err.Error())
+	assert.Equal([]int{1, 2, 3}, result)
+
+	err = stream.ForEachErr(func(item int) error {
+		return nil
+	})
+	assert.IsNil(err)
+}
+
+func TestStream_ForEachErrElem(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_ForEachErrElem")
+
+	stream := FromSlice([]string{"1", "2", "a", "b"})
+
+	item, err := stream.ForEachErrElem(func(item string) error {
+		_, err := strconv.Atoi(item)
+		return err
+	})
+
+	assert.Equal("a", item)
+	assert.IsNotNil(err)
+
+	item, err = stream.ForEachErrElem(func(item string) error {
+		return nil
+	})
+
+	assert.Equal("", item)
+	assert.IsNil(err)
+}
+
+func TestStream_ForEachSampledErrors(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_ForEachSampledErrors")
+
+	stream := FromRange(1, 10, 1)
+
+	calls := 0
+	errs, count := stream.ForEachSampledErrors(func(item int) error {
+		calls++
+		if item%2 == 0 {
+			return fmt.Errorf("error at %d", item)
+		}
+		return nil
+	}, 3)
+
+	assert.Equal(10, calls)
+	assert.Equal(5, count)
+	assert.Equal(3, len(errs))
+	assert.Equal("error at 2", errs[0].Error())
+	assert.Equal("error at 6", errs[2].Error())
+
+	errs, count = stream.ForEachSampledErrors(func(item int) error {
+		return nil
+	}, 3)
+
+	assert.Equal(0, count)
+	assert.Equal([]error{}, errs)
+}
+
+func TestStream_ForEachContext(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_ForEachContext")
+
+	stream := FromSlice([]int{1, 2, 3, 4, 5})
+
+	result := []int{}
+	err := stream.ForEachContext(context.Background(), func(item int) error {
+		result = append(result, item)
+		return nil
+	})
+	assert.IsNil(err)
+	assert.Equal([]int{1, 2, 3, 4, 5}, result)
+
+	errTest := errors.New("error at 3")
+	result = []int{}
+	err = stream.ForEachContext(context.Background(), func(item int) error {
+		result = append(result, item)
+		if item == 3 {
+			return errTest
+		}
+		return nil
+	})
+	assert.Equal(errTest, err)
+	assert.Equal([]int{1, 2, 3}, result)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	result = []int{}
+	err = stream.ForEachContext(ctx, func(item int) error {
+		result = append(result, item)
+		if item == 2 {
+			cancel()
+		}
+		return nil
+	})
+	assert.Equal(context.Canceled, err)
+	assert.Equal([]int{1, 2}, result)
+}
+
+func TestStream_ForEachTracked(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_ForEachTracked")
+
+	stream := FromSlice([]int{4, 2, 5, 1, 3})
+
+	sum := 0
+	min, max, ok := stream.ForEachTracked(func(item int) {
+		sum += item
+	}, Asc[int]())
+
+	assert.Equal(15, sum)
+	assert.Equal(1, min)
+	assert.Equal(5, max)
+	assert.Equal(true, ok)
+
+	calls := 0
+	min, max, ok = FromSlice([]int{}).ForEachTracked(func(item int) {
+		calls++
+	}, Asc[int]())
+
+	assert.Equal(0, calls)
+	assert.Equal(0, min)
+	assert.Equal(0, max)
+	assert.Equal(false, ok)
+}
+
+func TestStream_ForEachControlled(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_ForEachControlled")
+
+	stream := FromSlice([]int{1, 2, 3, 4, 5})
+
+	pause := make(chan bool, 1)
+	gate := make(chan struct{})
+	processed := make(chan int, stream.Count())
+	done := make(chan struct{})
+
+	go func() {
+		stream.ForEachControlled(func(item int) {
+			processed <- item
+			if item == 1 {
+				<-gate
+			}
+		}, pause)
+		close(done)
+	}()
+
+	assert.Equal(1, <-processed)
+
+	pause <- true
+	close(gate)
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(0, len(processed))
+
+	pause <- false
+	<-done
+
+	result := []int{}
+	for len(processed) > 0 {
+		result = append(result, <-processed)
+	}
+	assert.Equal([]int{2, 3, 4, 5}, result)
+}
+
+func TestStream_ForEachReverse(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_ForEachReverse")
+
+	stream := FromSlice([]int{1, 2, 3})
+
+	result := []int{}
+	stream.ForEachReverse(func(item int) {
+		result = append(result, item)
+	})
+
+	assert.Equal([]int{3, 2, 1}, result)
+	assert.Equal([]int{1, 2, 3}, stream.ToSlice())
+}
+
+func TestDispatch(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestDispatch")
+
+	stream := FromSlice([]int{0, 1, 2, 3, 4, 0, 5, -1})
+
+	zero, even, odd, others := []int{}, []int{}, []int{}, []int{}
+
+	Dispatch(stream,
+		Route[int]{
+			Predicate: func(n int) bool { return n == 0 },
+			Action:    func(n int) { zero = append(zero, n) },
+		},
+		Route[int]{
+			Predicate: func(n int) bool { return n > 0 && n%2 == 0 },
+			Action:    func(n int) { even = append(even, n) },
+		},
+		Route[int]{
+			Predicate: func(n int) bool { return n > 0 && n%2 == 1 },
+			Action:    func(n int) { odd = append(odd, n) },
+		},
+	)
+
+	assert.Equal([]int{0, 0}, zero)
+	assert.Equal([]int{2, 4}, even)
+	assert.Equal([]int{1, 3, 5}, odd)
+
+	Dispatch(stream,
+		Route[int]{
+			Predicate: func(n int) bool { return n >= 0 },
+			Action:    func(n int) {},
+		},
+		Route[int]{
+			Action: func(n int) { others = append(others, n) },
+		},
+	)
+
+	assert.Equal([]int{-1}, others)
+}
+
+func TestShardForEach(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestShardForEach")
+
+	stream := FromSlice([]string{"a", "b", "c", "a", "d", "b", "a"})
+
+	hasher := func(s string) uint64 {
+		h := fnv.New64a()
+		h.Write([]byte(s))
+		return h.Sum64()
+	}
+
+	shardOf := map[string]int{}
+	ShardForEach(stream, 3, hasher, func(shard int, item string) {
+		assert.Equal(true, shard >= 0 && shard < 3)
+		if prev, ok := shardOf[item]; ok {
+			assert.Equal(prev, shard)
+		}
+		shardOf[item] = shard
+	})
+
+	ShardForEach(stream, 3, hasher, func(shard int, item string) {
+		assert.Equal(shardOf[item], shard)
+	})
+
+	shards := [][]int{{}, {}}
+	ShardForEach(FromSlice([]int{1, 2, 3, 4}), 2, func(n int) uint64 { return uint64(n) }, func(shard int, item int) {
+		shards[shard] = append(shards[shard], item)
+	})
+	assert.Equal([][]int{{2, 4}, {1, 3}}, shards)
+}
+
+func TestStream_ParallelForEachOrderedComplete(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_ParallelForEachOrderedComplete")
+
+	stream := FromRange(0, 19, 1)
+
+	items := []int{}
+	results := []any{}
+
+	stream.ParallelForEachOrderedComplete(func(item int) any {
+		time.Sleep(time.Duration((item*7)%5) * time.Millisecond)
+		return item * item
+	}, func(item int, result any) {
+		items = append(items, item)
+		results = append(results, result)
+	}, 4)
+
+	expected := []any{}
+	stream.ForEach(func(item int) {
+		expected = append(expected, item*item)
+	})
+
+	assert.Equal(stream.ToSlice(), items)
+	assert.Equal(expected, results)
+
+	calls := 0
+	Empty[int]().ParallelForEachOrderedComplete(func(item int) any {
+		return item
+	}, func(item int, result any) {
+		calls++
+	}, 2)
+	assert.Equal(0, calls)
+}
+
+func TestStream_ParallelForEachGroup(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_ParallelForEachGroup")
+
+	stream := FromRange(1, 100, 1)
+
+	var sum int64
+	err := stream.ParallelForEachGroup(context.Background(), func(ctx context.Context, item int) error {
+		atomic.AddInt64(&sum, int64(item))
+		return nil
+	}, 4)
+
+	assert.IsNil(err)
+	assert.Equal(int64(5050), sum)
+
+	errTest := errors.New("error at 3")
+	var started, cancelled int64
+
+	err = stream.ParallelForEachGroup(context.Background(), func(ctx context.Context, item int) error {
+		atomic.AddInt64(&started, 1)
+		if item == 3 {
+			return errTest
+		}
+
+		select {
+		case <-ctx.Done():
+			atomic.AddInt64(&cancelled, 1)
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return nil
+		}
+	}, 4)
+
+	assert.Equal(errTest, err)
+	assert.Equal(true, atomic.LoadInt64(&started) < int64(stream.Count()))
+	assert.Equal(atomic.LoadInt64(&started)-1, atomic.LoadInt64(&cancelled))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = stream.ParallelForEachGroup(ctx, func(ctx context.Context, item int) error {
+		return nil
+	}, 4)
+	assert.Equal(context.Canceled, err)
+
+	// ctx is cancelled by the last action, after all elements are started.
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	var done int64
+	err = stream.ParallelForEachGroup(ctx, func(_ context.Context, item int) error {
+		if atomic.AddInt64(&done, 1) == int64(stream.Count()) {
+			cancel()
+		}
+		return nil
+	}, 4)
+	assert.IsNil(err)
+	assert.Equal(int64(stream.Count()), done)
+}
+
+func TestStream_Reduce(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_Reduce")
+
+	stream := FromSlice([]int{1, 2, 3})
+
+	result := stream.Reduce(0, func(a, b int) int {
+		return a + b
+	})
 
 	assert.Equal(6, result)
 }
 
-func TestStream_Reduce(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_Reduce")
+func TestStream_CountAtMost(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_CountAtMost")
+
+	stream := FromSlice([]int{1, 2, 3, 4, 5})
+
+	assert.Equal(3, stream.CountAtMost(3))
+	assert.Equal(5, stream.CountAtMost(5))
+	assert.Equal(5, stream.CountAtMost(10))
+	assert.Equal(0, stream.CountAtMost(0))
+	assert.Equal(0, stream.CountAtMost(-1))
+	assert.Equal(0, FromSlice([]int{}).CountAtMost(3))
+}
+
+func TestStream_CountBy(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_CountBy")
+
+	stream := FromSlice([]int{1, 2, 3, 4, 5})
+
+	isEven := func(n int) bool {
+		return n%2 == 0
+	}
+
+	assert.Equal(5, stream.CountBy(func(n int) bool { return n > 0 }))
+	assert.Equal(0, stream.CountBy(func(n int) bool { return n > 5 }))
+	assert.Equal(2, stream.CountBy(isEven))
+	assert.Equal(0, FromSlice([]int{}).CountBy(isEven))
+}
+
+func TestJoinToString(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestJoinToString")
+
+	stringify := func(n int) string {
+		return strconv.Itoa(n)
+	}
+
+	assert.Equal("", JoinToString(FromSlice([]int{}), ",", stringify))
+	assert.Equal("1", JoinToString(FromSlice([]int{1}), ",", stringify))
+	assert.Equal("1,2,3", JoinToString(FromSlice([]int{1, 2, 3}), ",", stringify))
+}
+
+func TestJoin(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestJoin")
+
+	assert.Equal("", Join(FromSlice([]string{}), ", "))
+	assert.Equal("a", Join(FromSlice([]string{"a"}), ", "))
+	assert.Equal("a, b, c", Join(FromSlice([]string{"a", "b", "c"}), ", "))
+}
+
+func TestReduceMonoid(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestReduceMonoid")
+
+	assert.Equal(6, ReduceMonoid(FromSlice([]int{1, 2, 3}), SumMonoid[int]()))
+	assert.Equal(0, ReduceMonoid(FromSlice([]int{}), SumMonoid[int]()))
+	assert.Equal(4.5, ReduceMonoid(FromSlice([]float64{1.5, 3}), SumMonoid[float64]()))
+	assert.Equal("abc", ReduceMonoid(FromSlice([]string{"a", "b", "c"}), ConcatMonoid()))
+
+	maxMonoid := Monoid[int]{
+		Identity: math.MinInt,
+		Combine: func(a, b int) int {
+			if a > b {
+				return a
+			}
+			return b
+		},
+	}
+
+	assert.Equal(9, ReduceMonoid(FromSlice([]int{3, 9, -1, 4}), maxMonoid))
+	assert.Equal(math.MinInt, ReduceMonoid(FromSlice([]int{}), maxMonoid))
+}
+
+func TestStream_Count(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_Count")
+
+	s1 := FromSlice([]int{1, 2, 3})
+	s2 := FromSlice([]int{})
+
+	assert.Equal(3, s1.Count())
+	assert.Equal(0, s2.Count())
+}
+
+func TestStream_FindFirst(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_FindFirst")
+
+	stream := FromSlice([]int{1, 2, 3})
+
+	result, ok := stream.FindFirst()
+
+	assert.Equal(1, result)
+	assert.Equal(true, ok)
+}
+
+func TestStream_FindLast(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_FindLast")
+
+	stream := FromSlice([]int{3, 2, 1})
+
+	result, ok := stream.FindLast()
+
+	assert.Equal(1, result)
+	assert.Equal(true, ok)
+
+	stream2 := FromSlice([]int{})
+
+	result, ok = stream2.FindLast()
+
+	assert.Equal(0, result)
+	assert.Equal(false, ok)
+}
+
+func TestStream_Find(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_Find")
+
+	stream := FromSlice([]int{1, 2, 3, 4, 5})
+
+	calls := 0
+	result, ok := stream.Find(func(item int) bool {
+		calls++
+		return item > 0
+	})
+	assert.Equal(1, result)
+	assert.Equal(true, ok)
+	assert.Equal(1, calls)
+
+	result, ok = stream.Find(func(item int) bool { return item%3 == 0 })
+	assert.Equal(3, result)
+	assert.Equal(true, ok)
+
+	result, ok = stream.Find(func(item int) bool { return item == 5 })
+	assert.Equal(5, result)
+	assert.Equal(true, ok)
+
+	result, ok = stream.Find(func(item int) bool { return item > 5 })
+	assert.Equal(0, result)
+	assert.Equal(false, ok)
+
+	result, ok = FromSlice([]int{}).Find(func(item int) bool { return true })
+	assert.Equal(0, result)
+	assert.Equal(false, ok)
+}
+
+func TestStream_ElementAt(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_ElementAt")
+
+	stream := FromSlice([]int{1, 2, 3})
+
+	result, ok := stream.ElementAt(0)
+	assert.Equal(1, result)
+	assert.Equal(true, ok)
+
+	result, ok = stream.ElementAt(2)
+	assert.Equal(3, result)
+	assert.Equal(true, ok)
+
+	result, ok = stream.ElementAt(-1)
+	assert.Equal(0, result)
+	assert.Equal(false, ok)
+
+	result, ok = stream.ElementAt(3)
+	assert.Equal(0, result)
+	assert.Equal(false, ok)
+
+	_, ok = FromSlice([]int{}).ElementAt(0)
+	assert.Equal(false, ok)
+}
+
+func TestStream_Reverse(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_Reverse")
+
+	s := FromSlice([]int{1, 2, 3})
+
+	rs := s.Reverse()
+
+	assert.Equal([]int{3, 2, 1}, rs.ToSlice())
+}
+
+func TestStream_Range(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_Range")
+
+	s := FromSlice([]int{1, 2, 3})
+
+	s1 := s.Range(-1, 0)
+	assert.Equal([]int{}, s1.ToSlice())
+
+	s2 := s.Range(0, -1)
+	assert.Equal([]int{}, s2.ToSlice())
+
+	s3 := s.Range(0, 0)
+	assert.Equal([]int{}, s3.ToSlice())
+
+	s4 := s.Range(1, 1)
+	assert.Equal([]int{}, s4.ToSlice())
+
+	s5 := s.Range(0, 1)
+	assert.Equal([]int{1}, s5.ToSlice())
+
+	s6 := s.Range(0, 4)
+	assert.Equal([]int{1, 2, 3}, s6.ToSlice())
+}
+
+func TestWindow(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestWindow")
+
+	s := FromSlice([]int{1, 2, 3, 4, 5})
+
+	s1 := Window(s, 3, 1)
+	assert.Equal([][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}, s1.ToSlice())
+
+	s2 := Window(s, 2, 2)
+	assert.Equal([][]int{{1, 2}, {3, 4}}, s2.ToSlice())
+
+	s3 := Window(s, 2, 3)
+	assert.Equal([][]int{{1, 2}, {4, 5}}, s3.ToSlice())
+
+	s4 := Window(s, 6, 1)
+	assert.Equal([][]int{}, s4.ToSlice())
+
+	defer func() {
+		r := recover()
+		assert.IsNotNil(r)
+	}()
+
+	Window(s, 0, 1)
+}
+
+func TestChunkPadded(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestChunkPadded")
+
+	s := FromRange(1, 7, 1)
+
+	chunks := ChunkPadded(s, 3, 0).ToSlice()
+	assert.Equal([][]int{{1, 2, 3}, {4, 5, 6}, {7, 0, 0}}, chunks)
+	assert.Equal(3, len(chunks[2]))
+
+	assert.Equal([][]int{{1, 2, 3, 4, 5, 6, 7}}, ChunkPadded(s, 7, 0).ToSlice())
+	assert.Equal([][]int{{1, 2, 3, 4, 5, 6, 7, -1}}, ChunkPadded(s, 8, -1).ToSlice())
+	assert.Equal([][]int{}, ChunkPadded(FromSlice([]int{}), 3, 0).ToSlice())
+
+	defer func() {
+		r := recover()
+		assert.IsNotNil(r)
+	}()
+
+	ChunkPadded(s, 0, 0)
+}
+
+func TestSessionWindow(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestSessionWindow")
+
+	base := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return base.Add(time.Duration(minutes) * time.Minute)
+	}
+
+	timestamps := FromSlice([]time.Time{at(0), at(3), at(5), at(30), at(32), at(60)})
+
+	maxGap := func(prev, curr time.Time) bool {
+		return curr.Sub(prev) <= 10*time.Minute
+	}
+	sessionStart := func(session []time.Time) time.Time {
+		return session[0]
+	}
+
+	sessions := SessionWindow(timestamps, maxGap, sessionStart)
+	assert.Equal([]time.Time{at(0), at(30), at(60)}, sessions.ToSlice())
+
+	sizes := SessionWindow(FromSlice([]int{1, 2, 3, 7, 8, 20}), func(prev, curr int) bool {
+		return curr-prev <= 2
+	}, func(session []int) int {
+		return len(session)
+	})
+	assert.Equal([]int{3, 2, 1}, sizes.ToSlice())
+
+	assert.Equal([]time.Time{}, SessionWindow(FromSlice([]time.Time{}), maxGap, sessionStart).ToSlice())
+}
+
+func TestStream_Concat(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_Concat")
+
+	s1 := FromSlice([]int{1, 2, 3})
+	s2 := FromSlice([]int{4, 5, 6})
+
+	s := Concat(s1, s2)
+
+	assert.Equal([]int{1, 2, 3, 4, 5, 6}, s.ToSlice())
+}
+
+func TestConcatDistinct(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestConcatDistinct")
+
+	s1 := FromSlice([]int{1, 2, 2, 3})
+	s2 := FromSlice([]int{3, 4, 1, 5})
+
+	assert.Equal([]int{1, 2, 3, 4, 5}, ConcatDistinct(s1, s2).ToSlice())
+	assert.Equal([]int{3, 4, 1, 5, 2}, ConcatDistinct(s2, s1).ToSlice())
+	assert.Equal([]int{}, ConcatDistinct[int]().ToSlice())
+}
+
+func TestStream_Sorted(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_Sorted")
+
+	s := FromSlice([]int{4, 2, 1, 3})
+
+	s1 := s.Sorted(func(a, b int) bool { return a < b })
+
+	assert.Equal([]int{4, 2, 1, 3}, s.ToSlice())
+	assert.Equal([]int{1, 2, 3, 4}, s1.ToSlice())
+}
+
+func TestStream_MinMax(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_MinMax")
+
+	s := FromSlice([]int{4, 2, 1, 5, 3})
+
+	min, max, ok := s.MinMax(func(a, b int) bool { return a < b })
+	expectedMin, _ := s.Min(func(a, b int) bool { return a < b })
+	expectedMax, _ := s.Max(func(a, b int) bool { return a > b })
+
+	assert.Equal(expectedMin, min)
+	assert.Equal(expectedMax, max)
+	assert.Equal(true, ok)
+
+	min, max, ok = FromSlice([]int{7}).MinMax(func(a, b int) bool { return a < b })
+	assert.Equal(7, min)
+	assert.Equal(7, max)
+	assert.Equal(true, ok)
+
+	_, _, ok = FromSlice([]int{}).MinMax(func(a, b int) bool { return a < b })
+	assert.Equal(false, ok)
+}
+
+func TestArgMinMax(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestArgMinMax")
+
+	minIdx, maxIdx, ok := ArgMinMax(FromSlice([]int{4, 1, 9, 3, 1, 9, 2}))
+	assert.Equal(1, minIdx)
+	assert.Equal(2, maxIdx)
+	assert.Equal(true, ok)
+
+	minIdx, maxIdx, ok = ArgMinMax(FromSlice([]float64{2.5}))
+	assert.Equal(0, minIdx)
+	assert.Equal(0, maxIdx)
+	assert.Equal(true, ok)
+
+	_, _, ok = ArgMinMax(FromSlice([]int{}))
+	assert.Equal(false, ok)
+}
+
+func TestComparators(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestComparators")
+
+	s := FromSlice([]int{4, 2, 1, 3})
+
+	assert.Equal([]int{1, 2, 3, 4}, s.Sorted(Asc[int]()).ToSlice())
+	assert.Equal([]int{4, 3, 2, 1}, s.Sorted(Desc[int]()).ToSlice())
+
+	min, _ := s.Min(Asc[int]())
+	max, _ := s.Max(Desc[int]())
+
+	assert.Equal(1, min)
+	assert.Equal(4, max)
+
+	type Person struct {
+		Name string
+		Age  int
+	}
+
+	people := FromSlice([]Person{
+		{Name: "Tom", Age: 30},
+		{Name: "Jim", Age: 10},
+		{Name: "Mike", Age: 20},
+	})
+
+	byAge := people.Sorted(ByKey(func(p Person) int { return p.Age }))
+	assert.Equal([]Person{
+		{Name: "Jim", Age: 10},
+		{Name: "Mike", Age: 20},
+		{Name: "Tom", Age: 30},
+	}, byAge.ToSlice())
+
+	byName := people.Sorted(ByKey(func(p Person) string { return p.Name }))
+	assert.Equal([]Person{
+		{Name: "Jim", Age: 10},
+		{Name: "Mike", Age: 20},
+		{Name: "Tom", Age: 30},
+	}, byName.ToSlice())
+}
+
+func TestTopN(t *testing.T) {
+	t.Parallel()
 
-	stream := FromSlice([]int{1, 2, 3})
+	assert := internal.NewAssert(t, "TestTopN")
 
-	result := stream.Reduce(0, func(a, b int) int {
-		return a + b
+	s := FromSlice([]int{5, 1, 9, 3, 7, 2, 8, 6, 4, 0})
+	less := func(a, b int) bool { return a < b }
+
+	sorted := s.Sorted(less).ToSlice()
+
+	assert.Equal(sorted[len(sorted)-3:], TopN(s, 3, less).ToSlice())
+	assert.Equal(sorted[len(sorted)-1:], TopN(s, 1, less).ToSlice())
+	assert.Equal(sorted, TopN(s, 10, less).ToSlice())
+	assert.Equal(sorted, TopN(s, 20, less).ToSlice())
+	assert.Equal([]int{}, TopN(s, 0, less).ToSlice())
+	assert.Equal([]int{}, TopN(s, -1, less).ToSlice())
+
+	assert.Equal([]int{5, 9, 9}, TopN(FromSlice([]int{9, 1, 9, 5}), 3, less).ToSlice())
+}
+
+func BenchmarkTopN(b *testing.B) {
+	source := make([]int, 100000)
+	for i := range source {
+		source[i] = (i * 7919) % 100003
+	}
+	s := FromSlice(source)
+	less := func(a, b int) bool { return a < b }
+
+	b.Run("TopN", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			result := TopN(s, 10, less)
+			if result.Count() != 10 {
+				b.Fatal("unexpected result count")
+			}
+		}
 	})
 
-	assert.Equal(6, result)
+	b.Run("SortedRange", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			result := s.Sorted(less).Range(len(source)-10, len(source))
+			if result.Count() != 10 {
+				b.Fatal("unexpected result count")
+			}
+		}
+	})
 }
 
-func TestStream_Count(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_Count")
+func TestStream_Max(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_Max")
 
-	s1 := FromSlice([]int{1, 2, 3})
-	s2 := FromSlice([]int{})
+	s := FromSlice([]int{4, 2, 1, 3})
 
-	assert.Equal(3, s1.Count())
-	assert.Equal(0, s2.Count())
+	max, ok := s.Max(func(a, b int) bool { return a > b })
+
+	assert.Equal(4, max)
+	assert.Equal(true, ok)
 }
 
-func TestStream_FindFirst(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_FindFirst")
+func TestStream_Min(t *testing.T) {
+	assert := internal.NewAssert(t, "TestStream_Min")
 
-	stream := FromSlice([]int{1, 2, 3})
+	s := FromSlice([]int{4, 2, 1, 3})
 
-	result, ok := stream.FindFirst()
+	max, ok := s.Max(func(a, b int) bool { return a < b })
 
-	assert.Equal(1, result)
+	assert.Equal(1, max)
 	assert.Equal(true, ok)
 }
 
-func TestStream_FindLast(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_FindLast")
+func TestStream_Iterator(t *testing.T) {
+	t.Parallel()
 
-	stream := FromSlice([]int{3, 2, 1})
+	assert := internal.NewAssert(t, "TestStream_Iterator")
 
-	result, ok := stream.FindLast()
+	stream := FromSlice([]int{1, 2, 3, 4, 5})
 
-	assert.Equal(1, result)
+	next := stream.Iterator()
+
+	item, ok := next()
+	assert.Equal(1, item)
 	assert.Equal(true, ok)
 
-	stream2 := FromSlice([]int{})
+	item, ok = next()
+	assert.Equal(2, item)
+	assert.Equal(true, ok)
 
-	result, ok = stream2.FindLast()
+	result := []int{}
+	next = stream.Iterator()
+	for item, ok := next(); ok; item, ok = next() {
+		result = append(result, item)
+	}
+	assert.Equal(stream.ToSlice(), result)
 
-	assert.Equal(0, result)
+	for i := 0; i < 2; i++ {
+		item, ok = next()
+		assert.Equal(0, item)
+		assert.Equal(false, ok)
+	}
+
+	_, ok = FromSlice([]int{}).Iterator()()
 	assert.Equal(false, ok)
 }
 
-func TestStream_Reverse(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_Reverse")
+func TestStream_Backward(t *testing.T) {
+	t.Parallel()
 
-	s := FromSlice([]int{1, 2, 3})
+	assert := internal.NewAssert(t, "TestStream_Backward")
 
-	rs := s.Reverse()
+	stream := FromSlice([]int{1, 2, 3, 4})
 
-	assert.Equal([]int{3, 2, 1}, rs.ToSlice())
+	result := []int{}
+	stream.Backward()(func(item int) bool {
+		result = append(result, item)
+		return true
+	})
+
+	expected := []int{}
+	source := stream.ToSlice()
+	for i := len(source) - 1; i >= 0; i-- {
+		expected = append(expected, source[i])
+	}
+
+	assert.Equal(expected, result)
+	assert.Equal(stream.Reverse().ToSlice(), result)
+
+	result = []int{}
+	stream.Backward()(func(item int) bool {
+		result = append(result, item)
+		return item > 3
+	})
+	assert.Equal([]int{4, 3}, result)
 }
 
-func TestStream_Range(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_Range")
+func TestStream_Tee(t *testing.T) {
+	t.Parallel()
 
-	s := FromSlice([]int{1, 2, 3})
+	assert := internal.NewAssert(t, "TestStream_Tee")
 
-	s1 := s.Range(-1, 0)
-	assert.Equal([]int{}, s1.ToSlice())
+	stream := FromSlice([]int{1, 2, 3})
 
-	s2 := s.Range(0, -1)
-	assert.Equal([]int{}, s2.ToSlice())
+	s1, s2 := stream.Tee()
 
-	s3 := s.Range(0, 0)
-	assert.Equal([]int{}, s3.ToSlice())
+	s1.ToSlice()[0] = 100
+	doubled := s2.Map(func(n int) int { return n * 2 })
 
-	s4 := s.Range(1, 1)
-	assert.Equal([]int{}, s4.ToSlice())
+	assert.Equal([]int{100, 2, 3}, s1.ToSlice())
+	assert.Equal([]int{1, 2, 3}, s2.ToSlice())
+	assert.Equal([]int{2, 4, 6}, doubled.ToSlice())
+	assert.Equal([]int{1, 2, 3}, stream.ToSlice())
 
-	s5 := s.Range(0, 1)
-	assert.Equal([]int{1}, s5.ToSlice())
+	assert.Equal(3, s2.Count())
+	assert.Equal(6, s2.Reduce(0, func(a, b int) int { return a + b }))
+}
 
-	s6 := s.Range(0, 4)
-	assert.Equal([]int{1, 2, 3}, s6.ToSlice())
+func TestToSliceAndIndex(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestToSliceAndIndex")
+
+	type Person struct {
+		Id   string
+		Name string
+	}
+
+	people := []Person{
+		{Id: "001", Name: "Tom"},
+		{Id: "002", Name: "Jim"},
+		{Id: "001", Name: "Tommy"},
+	}
+
+	result, index := ToSliceAndIndex(FromSlice(people), func(p Person) string { return p.Id })
+
+	assert.Equal(people, result)
+	assert.Equal(map[string]int{"001": 2, "002": 1}, index)
+
+	for id, i := range index {
+		assert.Equal(id, result[i].Id)
+	}
 }
 
-func TestStream_Concat(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_Concat")
+func TestStream_ToHeap(t *testing.T) {
+	t.Parallel()
 
-	s1 := FromSlice([]int{1, 2, 3})
-	s2 := FromSlice([]int{4, 5, 6})
+	assert := internal.NewAssert(t, "TestStream_ToHeap")
 
-	s := Concat(s1, s2)
+	stream := FromSlice([]int{5, 1, 4, 2, 3})
 
-	assert.Equal([]int{1, 2, 3, 4, 5, 6}, s.ToSlice())
+	h := stream.ToHeap(func(a, b int) bool { return a < b })
+	assert.Equal(5, h.Len())
+
+	h.Push(0)
+	h.Push(6)
+	assert.Equal(7, h.Len())
+
+	result := []int{}
+	for h.Len() > 0 {
+		v, ok := h.Pop()
+		assert.Equal(true, ok)
+		result = append(result, v)
+	}
+	assert.Equal([]int{0, 1, 2, 3, 4, 5, 6}, result)
+
+	_, ok := h.Pop()
+	assert.Equal(false, ok)
+
+	assert.Equal([]int{5, 1, 4, 2, 3}, stream.ToSlice())
+
+	maxHeap := stream.ToHeap(func(a, b int) bool { return a > b })
+	max, _ := maxHeap.Pop()
+	assert.Equal(5, max)
 }
 
-func TestStream_Sorted(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_Sorted")
+func TestHeap_ZeroValue(t *testing.T) {
+	t.Parallel()
 
-	s := FromSlice([]int{4, 2, 1, 3})
+	assert := internal.NewAssert(t, "TestHeap_ZeroValue")
 
-	s1 := s.Sorted(func(a, b int) bool { return a < b })
+	var h Heap[int]
 
-	assert.Equal([]int{4, 2, 1, 3}, s.ToSlice())
-	assert.Equal([]int{1, 2, 3, 4}, s1.ToSlice())
+	assert.Equal(0, h.Len())
+
+	_, ok := h.Pop()
+	assert.Equal(false, ok)
+
+	defer func() {
+		r := recover()
+		assert.IsNotNil(r)
+	}()
+
+	h.Push(1)
 }
 
-func TestStream_Max(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_Max")
+func TestToSet(t *testing.T) {
+	t.Parallel()
 
-	s := FromSlice([]int{4, 2, 1, 3})
+	assert := internal.NewAssert(t, "TestToSet")
 
-	max, ok := s.Max(func(a, b int) bool { return a > b })
+	s1 := FromSlice([]int{1, 2, 2, 3, 3, 3})
+	s2 := FromSlice([]int{})
 
-	assert.Equal(4, max)
+	assert.Equal(map[int]struct{}{1: {}, 2: {}, 3: {}}, ToSet(s1))
+
+	set := ToSet(s2)
+	assert.IsNotNil(set)
+	assert.Equal(0, len(set))
+}
+
+func TestStream_WriteCSV(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestStream_WriteCSV")
+
+	type Person struct {
+		Name string
+		Age  int
+	}
+
+	people := []Person{
+		{Name: "Tom", Age: 10},
+		{Name: "Jim, Jr.", Age: 20},
+		{Name: "Mike", Age: 30},
+	}
+
+	var buf bytes.Buffer
+
+	err := FromSlice(people).WriteCSV(&buf, func(item Person) []string {
+		return []string{item.Name, strconv.Itoa(item.Age)}
+	})
+	assert.IsNil(err)
+
+	stream, err := FromCSV(&buf, func(record []string) (Person, error) {
+		age, err := strconv.Atoi(record[1])
+		return Person{Name: record[0], Age: age}, err
+	})
+
+	assert.IsNil(err)
+	assert.Equal(people, stream.ToSlice())
+}
+
+func TestGroupByCount(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestGroupByCount")
+
+	words := FromSlice([]string{"apple", "avocado", "banana", "blueberry", "cherry", "apricot"})
+
+	byFirstLetter := GroupByCount(words, func(item string) byte {
+		return item[0]
+	})
+	assert.Equal(map[byte]int{'a': 3, 'b': 2, 'c': 1}, byFirstLetter)
+
+	nums := FromSlice([]int{1, 2, 3, 4, 5})
+
+	byParity := GroupByCount(nums, func(item int) string {
+		if item%2 == 0 {
+			return "even"
+		}
+		return "odd"
+	})
+	assert.Equal(map[string]int{"even": 2, "odd": 3}, byParity)
+
+	empty := GroupByCount(FromSlice([]int{}), func(item int) int { return item })
+	assert.IsNotNil(empty)
+	assert.Equal(0, len(empty))
+}
+
+func TestSortedCountBy(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestSortedCountBy")
+
+	stream := FromSlice([]int{35, 3, 12, 27, 18, 5, 31, 9, 14})
+
+	result := SortedCountBy(stream, func(item int) int {
+		return item / 10 * 10
+	})
+
+	assert.Equal([]Pair[int, int]{
+		{Key: 0, Val: 3},
+		{Key: 10, Val: 3},
+		{Key: 20, Val: 1},
+		{Key: 30, Val: 2},
+	}, result)
+
+	assert.Equal([]Pair[int, int]{}, SortedCountBy(FromSlice([]int{}), func(item int) int { return item }))
+}
+
+func TestOrderedMap_ZeroValue(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestOrderedMap_ZeroValue")
+
+	var om OrderedMap[string, int]
+
+	_, ok := om.Get("a")
+	assert.Equal(false, ok)
+	assert.Equal(0, om.Len())
+	assert.Equal([]string{}, om.Keys())
+
+	om.Set("b", 2)
+	om.Set("a", 1)
+
+	value, ok := om.Get("a")
+	assert.Equal(1, value)
 	assert.Equal(true, ok)
+	assert.Equal([]string{"b", "a"}, om.Keys())
+	assert.Equal([]int{2, 1}, om.Values())
 }
 
-func TestStream_Min(t *testing.T) {
-	assert := internal.NewAssert(t, "TestStream_Min")
+func TestToOrderedMap(t *testing.T) {
+	t.Parallel()
 
-	s := FromSlice([]int{4, 2, 1, 3})
+	assert := internal.NewAssert(t, "TestToOrderedMap")
 
-	max, ok := s.Max(func(a, b int) bool { return a < b })
+	type Person struct {
+		Id   string
+		Name string
+	}
 
-	assert.Equal(1, max)
+	stream := FromSlice([]Person{
+		{Id: "003", Name: "Mike"},
+		{Id: "001", Name: "Tom"},
+		{Id: "002", Name: "Jim"},
+		{Id: "001", Name: "Tommy"},
+	})
+
+	om := ToOrderedMap(stream, func(p Person) string { return p.Id }, func(p Person) string { return p.Name })
+
+	assert.Equal(3, om.Len())
+	assert.Equal([]string{"003", "001", "002"}, om.Keys())
+	assert.Equal([]string{"Mike", "Tommy", "Jim"}, om.Values())
+
+	name, ok := om.Get("001")
+	assert.Equal("Tommy", name)
 	assert.Equal(true, ok)
+
+	_, ok = om.Get("004")
+	assert.Equal(false, ok)
+
+	keys := []string{}
+	om.ForEach(func(key string, value string) {
+		keys = append(keys, key)
+	})
+	assert.Equal([]string{"003", "001", "002"}, keys)
+}
+
+func TestPartitionByWeight(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestPartitionByWeight")
+
+	stream := FromSlice([]int{1, 9, 2, 8, 3, 1, 1, 7})
+
+	weight := func(n int) float64 {
+		return float64(n)
+	}
+
+	result := PartitionByWeight(stream, 3, weight)
+
+	assert.Equal(3, len(result))
+
+	count := 0
+	totals := []float64{}
+	for _, group := range result {
+		total := 0.0
+		for _, n := range group {
+			total += weight(n)
+		}
+		count += len(group)
+		totals = append(totals, total)
+	}
+
+	assert.Equal(stream.Count(), count)
+
+	min, _ := FromSlice(totals).Min(Asc[float64]())
+	max, _ := FromSlice(totals).Max(Desc[float64]())
+	assert.Equal(true, max-min <= 1)
+
+	result = PartitionByWeight(FromSlice([]int{5}), 2, weight)
+	assert.Equal([][]int{{5}, {}}, result)
+
+	defer func() {
+		r := recover()
+		assert.IsNotNil(r)
+	}()
+
+	PartitionByWeight(stream, 0, weight)
+}
+
+func TestGroupByWindow(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestGroupByWindow")
+
+	type Event struct {
+		Name string
+		At   time.Time
+	}
+
+	base := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return base.Add(time.Duration(minutes) * time.Minute)
+	}
+
+	events := FromSlice([]Event{
+		{Name: "a", At: at(10)},
+		{Name: "b", At: at(50)},
+		{Name: "c", At: at(130)},
+		{Name: "d", At: at(70)},
+		{Name: "e", At: at(0)},
+	})
+
+	windows := GroupByWindow(events, func(e Event) time.Time { return e.At }, time.Hour)
+
+	assert.Equal([]Pair[time.Time, []Event]{
+		{Key: at(0), Val: []Event{{Name: "e", At: at(0)}, {Name: "a", At: at(10)}, {Name: "b", At: at(50)}}},
+		{Key: at(60), Val: []Event{{Name: "d", At: at(70)}}},
+		{Key: at(120), Val: []Event{{Name: "c", At: at(130)}}},
+	}, windows.ToSlice())
+
+	empty := GroupByWindow(FromSlice([]Event{}), func(e Event) time.Time { return e.At }, time.Hour)
+	assert.Equal(0, empty.Count())
+}
+
+func TestBucketize(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestBucketize")
+
+	stream := FromSlice([]int{7, 2, 9, 4, 1, 10, 3, 8, 5, 6})
+
+	buckets := Bucketize(stream, 4)
+
+	assert.Equal([][]int{{1, 2, 3}, {4, 5, 6}, {7, 8}, {9, 10}}, buckets)
+	assert.Equal([]int{7, 2, 9, 4, 1, 10, 3, 8, 5, 6}, stream.ToSlice())
+
+	assert.Equal([][]int{{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}}, Bucketize(stream, 1))
+	assert.Equal([][]int{{1, 2}, {3, 4}, {5, 6}, {7, 8}, {9, 10}}, Bucketize(stream, 5))
+	assert.Equal([][]int{{1}, {2}, {}}, Bucketize(FromSlice([]int{2, 1}), 3))
+	assert.Equal([][]int{{}, {}}, Bucketize(FromSlice([]int{}), 2))
+
+	defer func() {
+		r := recover()
+		assert.IsNotNil(r)
+	}()
+
+	Bucketize(stream, 0)
+}
+
+func TestDiff3(t *testing.T) {
+	t.Parallel()
+
+	assert := internal.NewAssert(t, "TestDiff3")
+
+	oldStream := FromSlice([]int{1, 2, 3, 2, 4, 5})
+	newStream := FromSlice([]int{6, 4, 3, 7, 6, 1})
+
+	added, removed, common := Diff3(oldStream, newStream)
+
+	assert.Equal([]int{6, 7}, added)
+	assert.Equal([]int{2, 5}, removed)
+	assert.Equal([]int{1, 3, 4}, common)
+
+	added, removed, common = Diff3(FromSlice([]int{}), FromSlice([]int{1}))
+
+	assert.Equal([]int{1}, added)
+	assert.Equal([]int{}, removed)
+	assert.Equal([]int{}, common)
 }